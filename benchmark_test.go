@@ -1,5 +1,36 @@
 package there_test
 
+import (
+	"fmt"
+	. "github.com/Gebes/there/v2"
+	"net/http/httptest"
+	"testing"
+)
+
+//BenchmarkThereRouteCount shows how the lookup scales with the amount of registered routes
+func BenchmarkThereRouteCount(b *testing.B) {
+	for _, count := range []int{10, 100, 600, 1000} {
+		router := NewRouter()
+		for i := 0; i < count; i++ {
+			router.Get(fmt.Sprintf("/resource%d/:id/details", i), func(request HttpRequest) HttpResponse {
+				return Status(StatusOK)
+			})
+		}
+		url := fmt.Sprintf("/resource%d/42/details", count-1)
+
+		b.Run(fmt.Sprintf("%d routes", count), func(b *testing.B) {
+			request := httptest.NewRequest(MethodGet, url, nil)
+			for i := 0; i < b.N; i++ {
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, request)
+				if recorder.Code != StatusOK {
+					b.Fatalf("unexpected status %d", recorder.Code)
+				}
+			}
+		})
+	}
+}
+
 /* Useful for testing. Commented for dependency cleanup
 import (
 	"encoding/json"
@@ -0,0 +1,240 @@
+package there
+
+import (
+	"encoding"
+	"errors"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//bindSources are the struct tags Bind reads, in the order they are checked
+var bindSources = []string{"param", "query", "header", "form"}
+
+//FieldError describes why a single struct field could not be bound
+type FieldError struct {
+	//Field is the path of the struct field, e.g. Filter.Page
+	Field string
+	//Source is the struct tag the value was read from: param, query, header or form
+	Source string
+	Key    string
+	Value  string
+	Err    error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": cannot bind " + e.Source + " \"" + e.Key + "\" with value \"" + e.Value + "\": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+//BindError lists every field Bind could not set
+type BindError struct {
+	Fields []FieldError
+}
+
+func (e BindError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return "could not bind request: " + strings.Join(messages, "; ")
+}
+
+//Bind sets the fields of dest, which must be a pointer to a struct, from the request.
+//The struct tags param, query, header and form specify the key of the value:
+//
+//	type Filter struct {
+//		ID     int        `param:"id"`
+//		Page   *int       `query:"page"`
+//		Tags   []string   `query:"tag"`
+//		Tenant string     `header:"X-Tenant"`
+//		Since  time.Time  `query:"since" layout:"2006-01-02"`
+//		Name   string     `form:"name"`
+//	}
+//
+//Strings, ints, uints, floats, bools, time.Duration, time.Time (RFC 3339 unless a layout tag is given),
+//encoding.TextUnmarshaler implementations, slices and pointers of them are supported.
+//Missing values leave the field untouched. If a value cannot be converted, a BindError listing every failing field is returned.
+//Afterwards the validate struct tags are checked, see Validate.
+func (r *HttpRequest) Bind(dest any) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.New("bind destination must be a non nil pointer to a struct")
+	}
+
+	var fieldErrors []FieldError
+	err := r.bindStruct(value.Elem(), "", &fieldErrors)
+	if err != nil {
+		return err
+	}
+	if len(fieldErrors) != 0 {
+		return BindError{Fields: fieldErrors}
+	}
+	return Validate(dest)
+}
+
+func (r *HttpRequest) bindStruct(value reflect.Value, prefix string, fieldErrors *[]FieldError) error {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldValue := value.Field(i)
+
+		source, key := bindTag(field)
+		if source == "" {
+			if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+				if err := r.bindStruct(fieldValue, prefix+field.Name+".", fieldErrors); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		values, err := r.bindValues(source, key)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			continue
+		}
+		if err = setField(fieldValue, values, field.Tag.Get("layout")); err != nil {
+			*fieldErrors = append(*fieldErrors, FieldError{
+				Field:  prefix + field.Name,
+				Source: source,
+				Key:    key,
+				Value:  strings.Join(values, ","),
+				Err:    err,
+			})
+		}
+	}
+	return nil
+}
+
+func bindTag(field reflect.StructField) (string, string) {
+	for _, source := range bindSources {
+		if key, ok := field.Tag.Lookup(source); ok && key != "-" {
+			if key == "" {
+				key = field.Name
+			}
+			return source, key
+		}
+	}
+	return "", ""
+}
+
+//bindValues returns every value for the key of the given source
+func (r *HttpRequest) bindValues(source, key string) ([]string, error) {
+	switch source {
+	case "param":
+		if value, ok := r.RouteParams.Get(key); ok {
+			return []string{value}, nil
+		}
+		return nil, nil
+	case "query":
+		values, _ := r.Params.GetSlice(key)
+		return values, nil
+	case "header":
+		values, _ := r.Headers.GetSlice(textproto.CanonicalMIMEHeaderKey(key))
+		return values, nil
+	default:
+		form, err := r.Body.Form()
+		if err != nil {
+			return nil, err
+		}
+		return form[key], nil
+	}
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+//setField converts the values to the type of the field. Only slices use more than the first value.
+func setField(field reflect.Value, values []string, layout string) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setField(slice.Index(i), []string{value}, layout); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		pointer := reflect.New(field.Type().Elem())
+		if err := setField(pointer.Elem(), values, layout); err != nil {
+			return err
+		}
+		field.Set(pointer)
+		return nil
+	}
+	return setValue(field, values[0], layout)
+}
+
+func setValue(field reflect.Value, value string, layout string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) && field.Type() != timeType {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Type() {
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		parsed, err := time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	case durationType:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return errors.New("unsupported type " + field.Type().String())
+	}
+	return nil
+}
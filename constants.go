@@ -182,7 +182,10 @@ const (
 	ContentTypeApplicationXDashShockwaveDashFlash        = "application/x-shockwave-flash"
 	ContentTypeApplicationJson                           = "application/json"
 	ContentTypeApplicationLdPlusJson                     = "application/ld+json"
+	ContentTypeApplicationProblemPlusJson                = "application/problem+json"
 	ContentTypeApplicationXml                            = "application/xml"
+	ContentTypeApplicationYaml                           = "application/yaml"
+	ContentTypeApplicationXDashMsgpack                   = "application/x-msgpack"
 	ContentTypeApplicationZip                            = "application/zip"
 	ContentTypeApplicationXDashWwwDashFormDashUrlencoded = "application/x-www-form-urlencoded"
 	ContentTypeAudioMpeg                                 = "audio/mpeg"
@@ -203,6 +206,7 @@ const (
 	ContentTypeMultipartFormDashData                     = "multipart/form-data"
 	ContentTypeTextCss                                   = "text/css"
 	ContentTypeTextCsv                                   = "text/csv"
+	ContentTypeTextEventDashStream                       = "text/event-stream"
 	ContentTypeTextHtml                                  = "text/html"
 	ContentTypeTextJavascript                            = "text/javascript"
 	ContentTypeTextPlain                                 = "text/plain"
@@ -383,6 +387,12 @@ const (
 	//	If-Unmodified-Since: Sat, 29 Oct 1994 19:43:31 GMT
 	RequestHeaderIfUnmodifiedSince = "If-Unmodified-Since"
 
+	// RequestHeaderLastEventID
+	// The ID of the last Server-Sent Event an EventSource received, sent when it reconnects.
+	//
+	//	Last-Event-ID: 42
+	RequestHeaderLastEventID = "Last-Event-ID"
+
 	// RequestHeaderMaxForwards
 	// Limit the number of times the message can be forwarded through proxies or gateways.
 	//
@@ -455,6 +465,18 @@ const (
 	//	Upgrade: h2c, HTTPS/1.3, IRC/6.9, RTA/x11, websocket
 	RequestHeaderUpgrade = "Upgrade"
 
+	// RequestHeaderSecWebSocketKey
+	// A random base64 encoded nonce, which the server proves to have received in the WebSocket handshake.
+	//
+	//	Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==
+	RequestHeaderSecWebSocketKey = "Sec-WebSocket-Key"
+
+	// RequestHeaderSecWebSocketVersion
+	// The WebSocket protocol version the client wants to use.
+	//
+	//	Sec-WebSocket-Version: 13
+	RequestHeaderSecWebSocketVersion = "Sec-WebSocket-Version"
+
 	// RequestHeaderVia
 	// Informs the server of proxies through which the request was sent.
 	//
@@ -729,6 +751,18 @@ const (
 	//	Upgrade: h2c, HTTPS/1.3, IRC/6.9, RTA/x11, websocket
 	ResponseHeaderUpgrade = "Upgrade"
 
+	// ResponseHeaderSecWebSocketAccept
+	// Confirms the WebSocket handshake with the hashed Sec-WebSocket-Key of the request.
+	//
+	//	Sec-WebSocket-Accept: s3pPLMBiTxaQ9kYGzzhZRbK+xOo=
+	ResponseHeaderSecWebSocketAccept = "Sec-WebSocket-Accept"
+
+	// ResponseHeaderSecWebSocketVersion
+	// The WebSocket protocol versions the server supports, sent if the requested one is not supported.
+	//
+	//	Sec-WebSocket-Version: 13
+	ResponseHeaderSecWebSocketVersion = "Sec-WebSocket-Version"
+
 	// ResponseHeaderVary
 	// Tells downstream proxies how to match future request headers to decide whether the cached response can be used rather than requesting a fresh one from the origin server.
 	//Example 1:
@@ -0,0 +1,173 @@
+package there
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+//ErrCookieInvalid is returned, if a signed or encrypted cookie was tampered with or none of the RouterConfiguration.CookieKeys matches
+var ErrCookieInvalid = errors.New("cookie is invalid")
+
+//ErrNoCookieKeys is returned, if signed or encrypted cookies are used without RouterConfiguration.CookieKeys
+var ErrNoCookieKeys = errors.New("there are no cookie keys configured")
+
+//WithCookies sets the cookies and writes the Http Response
+func WithCookies(cookies []*http.Cookie, response HttpResponse) HttpResponse {
+	return &cookieResponse{cookies: cookies, response: response}
+}
+
+//WithSignedCookies works like WithCookies, but signs the values with the first of the RouterConfiguration.CookieKeys.
+//The values stay readable for the client, but cannot be changed. Read them with HttpRequest.SignedCookie.
+func WithSignedCookies(cookies []*http.Cookie, response HttpResponse) HttpResponse {
+	return &cookieResponse{cookies: cookies, response: response, encode: signCookie}
+}
+
+//WithEncryptedCookies works like WithCookies, but encrypts the values with AES-GCM and the first of the RouterConfiguration.CookieKeys.
+//The values can neither be read nor changed by the client. Read them with HttpRequest.EncryptedCookie.
+func WithEncryptedCookies(cookies []*http.Cookie, response HttpResponse) HttpResponse {
+	return &cookieResponse{cookies: cookies, response: response, encode: encryptCookie}
+}
+
+type cookieResponse struct {
+	cookies  []*http.Cookie
+	response HttpResponse
+	//encode replaces the value of the cookie, it is nil for plain cookies
+	encode func(secret []byte, name, value string) (string, error)
+}
+
+func (c cookieResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	for _, cookie := range c.cookies {
+		if c.encode != nil {
+			keys := configuration(r).CookieKeys
+			if len(keys) == 0 {
+				panic(ErrNoCookieKeys)
+			}
+			value, err := c.encode(keys[0], cookie.Name, cookie.Value)
+			if err != nil {
+				panic(err)
+			}
+			encoded := *cookie
+			encoded.Value = value
+			cookie = &encoded
+		}
+		http.SetCookie(rw, cookie)
+	}
+	if c.response != nil {
+		c.response.ServeHTTP(rw, r)
+	}
+}
+
+//SignedCookie returns the value of a cookie set with WithSignedCookies.
+//Returns http.ErrNoCookie, if there is no such cookie and ErrCookieInvalid, if none of the keys verifies it.
+func (r *HttpRequest) SignedCookie(name string) (string, error) {
+	return r.decodeCookie(name, verifyCookie)
+}
+
+//EncryptedCookie returns the value of a cookie set with WithEncryptedCookies.
+//Returns http.ErrNoCookie, if there is no such cookie and ErrCookieInvalid, if none of the keys decrypts it.
+func (r *HttpRequest) EncryptedCookie(name string) (string, error) {
+	return r.decodeCookie(name, decryptCookie)
+}
+
+//decodeCookie tries every key, so cookies created with a previous key stay valid after a rotation
+func (r *HttpRequest) decodeCookie(name string, decode func(secret []byte, name, value string) (string, error)) (string, error) {
+	value, ok := r.Cookies.Get(name)
+	if !ok {
+		return "", http.ErrNoCookie
+	}
+	keys := configuration(r.Request).CookieKeys
+	if len(keys) == 0 {
+		return "", ErrNoCookieKeys
+	}
+	for _, key := range keys {
+		if decoded, err := decode(key, name, value); err == nil {
+			return decoded, nil
+		}
+	}
+	return "", ErrCookieInvalid
+}
+
+//deriveCookieKey derives independent keys for signing and encrypting from a single secret
+func deriveCookieKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("there cookie " + purpose))
+	return mac.Sum(nil)
+}
+
+//cookieMac authenticates the name and the value. The name is prefixed with its length,
+//so the boundary between them cannot be moved to sign a different name.
+func cookieMac(secret []byte, name, value string) []byte {
+	mac := hmac.New(sha256.New, deriveCookieKey(secret, "signing"))
+	var length [8]byte
+	binary.BigEndian.PutUint64(length[:], uint64(len(name)))
+	mac.Write(length[:])
+	mac.Write([]byte(name))
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func signCookie(secret []byte, name, value string) (string, error) {
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString([]byte(value)) + "." + encoding.EncodeToString(cookieMac(secret, name, value)), nil
+}
+
+func verifyCookie(secret []byte, name, signed string) (string, error) {
+	encoded, signature, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", ErrCookieInvalid
+	}
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrCookieInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, cookieMac(secret, name, string(value))) {
+		return "", ErrCookieInvalid
+	}
+	return string(value), nil
+}
+
+func cookieCipher(secret []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveCookieKey(secret, "encryption"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+//encryptCookie seals the value with a random nonce, the name is authenticated as additional data
+func encryptCookie(secret []byte, name, value string) (string, error) {
+	aead, err := cookieCipher(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(name))), nil
+}
+
+func decryptCookie(secret []byte, name, encrypted string) (string, error) {
+	aead, err := cookieCipher(secret)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encrypted)
+	if err != nil || len(data) < aead.NonceSize() {
+		return "", ErrCookieInvalid
+	}
+	value, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(name))
+	if err != nil {
+		return "", ErrCookieInvalid
+	}
+	return string(value), nil
+}
@@ -0,0 +1,213 @@
+package there
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//ErrorRenderer creates the response for an error rendered with the given StatusCode
+type ErrorRenderer func(request *http.Request, code int, err error) HttpResponse
+
+//errorBody renders as {"error": "..."} in JSON and <error><message>...</message></error> in XML.
+//The messages of ValidationErrors are added as {"fields": {"name": ["..."]}} and <field name="name"><message>...</message></field>.
+type errorBody struct {
+	XMLName   xml.Name            `json:"-" xml:"error"`
+	Error     string              `json:"error" xml:"message"`
+	Fields    map[string][]string `json:"fields,omitempty" xml:"-"`
+	XMLFields []errorField        `json:"-" xml:"field"`
+}
+
+type errorField struct {
+	Name     string   `xml:"name,attr"`
+	Messages []string `xml:"message"`
+}
+
+//DefaultErrorRenderer renders {"error": "..."} in the RouterConfiguration.DefaultMediaType
+func DefaultErrorRenderer(request *http.Request, code int, err error) HttpResponse {
+	return Serialize(code, configuration(request).DefaultMediaType, newErrorBody(err))
+}
+
+//NegotiatingErrorRenderer works like DefaultErrorRenderer, but renders the media type negotiated from the Accept header.
+//Unlike Negotiate, it falls back to the DefaultMediaType instead of failing. Browsers prefer XML over JSON with their default Accept header.
+func NegotiatingErrorRenderer(request *http.Request, code int, err error) HttpResponse {
+	config := configuration(request)
+	mediaType, ok := negotiateMediaType(request.Header.Get(RequestHeaderAccept), config)
+	if !ok {
+		mediaType = config.DefaultMediaType
+	}
+	return Serialize(code, mediaType, newErrorBody(err))
+}
+
+func newErrorBody(err error) errorBody {
+	body := errorBody{Error: err.Error()}
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		body.Fields = validationErrors.Fields()
+		//the fields keep the order of the ValidationErrors in XML
+		index := map[string]int{}
+		for _, e := range validationErrors {
+			i, ok := index[e.Field]
+			if !ok {
+				i = len(body.XMLFields)
+				index[e.Field] = i
+				body.XMLFields = append(body.XMLFields, errorField{Name: e.Field})
+			}
+			body.XMLFields[i].Messages = append(body.XMLFields[i].Messages, e.Message)
+		}
+	}
+	return body
+}
+
+//ProblemDetailsRenderer renders errors as RFC 7807 ProblemDetails.
+//Errors wrapping a ProblemDetails are rendered as is, the status and instance are filled in if missing.
+//The messages of ValidationErrors are added as "fields" extension.
+func ProblemDetailsRenderer(request *http.Request, code int, err error) HttpResponse {
+	var problem ProblemDetails
+	var validationErrors ValidationErrors
+	switch {
+	case errors.As(err, &problem):
+	case errors.As(err, &validationErrors):
+		problem = ProblemDetails{
+			Title:      StatusText(code),
+			Detail:     err.Error(),
+			Extensions: Map{"fields": validationErrors.Fields()},
+		}
+	default:
+		problem = ProblemDetails{
+			Title:  StatusText(code),
+			Detail: err.Error(),
+		}
+	}
+	if problem.Status == 0 {
+		problem.Status = code
+	}
+	if problem.Instance == "" {
+		problem.Instance = request.URL.Path
+	}
+	return Problem(problem)
+}
+
+//ProblemDetails describes an error as defined in RFC 7807. It can be returned as error and rendered with Problem.
+type ProblemDetails struct {
+	//Type is a URI identifying the problem type, "about:blank" if empty
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	//Extensions are additional members of the problem, e.g. the invalid fields
+	Extensions Map
+}
+
+func (p ProblemDetails) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	if p.Title != "" {
+		return p.Title
+	}
+	return StatusText(p.Status)
+}
+
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	members := Map{}
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+	members["type"] = p.Type
+	if p.Type == "" {
+		members["type"] = "about:blank"
+	}
+	if p.Title != "" {
+		members["title"] = p.Title
+	}
+	if p.Status != 0 {
+		members["status"] = p.Status
+	}
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	return json.Marshal(members)
+}
+
+//Problem renders the ProblemDetails as application/problem+json with its Status, StatusInternalServerError if it has none.
+//The Serializer for ContentTypeApplicationJson is used.
+func Problem(problem ProblemDetails) HttpResponse {
+	code := problem.Status
+	if code == 0 {
+		code = StatusInternalServerError
+	}
+	return WithHeaders(MapString{
+		ResponseHeaderContentType: ContentTypeApplicationProblemPlusJson,
+	}, Json(code, problem))
+}
+
+//ErrorMapper returns the StatusCode an error returned by an ErrorEndpoint is rendered with
+type ErrorMapper func(err error) int
+
+//StatusCoder can be implemented by errors to choose their StatusCode, even if they are wrapped
+type StatusCoder interface {
+	StatusCode() int
+}
+
+//DefaultErrorMapper maps errors implementing StatusCoder and ProblemDetails to their StatusCode,
+//the errors of this package to the matching client errors and everything else to StatusInternalServerError.
+var DefaultErrorMapper ErrorMapper = func(err error) int {
+	var coder StatusCoder
+	var problem ProblemDetails
+	switch {
+	case errors.As(err, &coder):
+		return coder.StatusCode()
+	case errors.As(err, &problem) && problem.Status != 0:
+		return problem.Status
+	case errors.As(err, &ValidationErrors{}):
+		return StatusUnprocessableEntity
+	case errors.As(err, &BindError{}), errors.Is(err, ErrParamMissing), errors.Is(err, ErrCookieInvalid):
+		return StatusBadRequest
+	case errors.Is(err, ErrBodyTooLarge):
+		return StatusRequestEntityTooLarge
+	default:
+		return StatusInternalServerError
+	}
+}
+
+//Is returns an ErrorMapper, which maps errors matching target with errors.Is to code and all others with m.
+//Later calls take precedence:
+//
+//	router.Configuration.ErrorMapper = DefaultErrorMapper.
+//		Is(sql.ErrNoRows, StatusNotFound).
+//		Is(ErrForbidden, StatusForbidden)
+func (m ErrorMapper) Is(target error, code int) ErrorMapper {
+	return func(err error) int {
+		if errors.Is(err, target) {
+			return code
+		}
+		return m(err)
+	}
+}
+
+//errorEndpointResponse renders an error returned by an ErrorEndpoint
+func errorEndpointResponse(err error) HttpResponse {
+	return HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var validationErrors ValidationErrors
+		if errors.As(err, &validationErrors) {
+			ValidationFailed(validationErrors).ServeHTTP(rw, r)
+			return
+		}
+		Error(configuration(r).ErrorMapper(err), err).ServeHTTP(rw, r)
+	})
+}
+
+//toError converts any value passed to Error, e.g. by a recovered panic
+func toError(err any) error {
+	if e, ok := err.(error); ok {
+		return e
+	}
+	return fmt.Errorf("%v", err)
+}
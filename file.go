@@ -0,0 +1,108 @@
+package there
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Stream takes a StatusCode, the Content-Type and a reader, which is copied to the client with chunked transfer encoding.
+//Every chunk is flushed immediately, so the data never has to fit into memory. The reader is closed afterwards, if it is an io.Closer.
+func Stream(code int, contentType string, reader io.Reader) HttpResponse {
+	return &streamResponse{code: code, contentType: contentType, reader: reader}
+}
+
+type streamResponse struct {
+	code        int
+	contentType string
+	reader      io.Reader
+}
+
+func (s streamResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if closer, ok := s.reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if s.contentType != "" {
+		rw.Header().Set(ResponseHeaderContentType, s.contentType)
+	}
+	rw.WriteHeader(s.code)
+
+	var writer io.Writer = rw
+	if flusher, ok := rw.(http.Flusher); ok {
+		writer = flushWriter{rw, flusher}
+	}
+	if _, err := io.Copy(writer, s.reader); err != nil {
+		// the status is already sent, aborting the response tells the client it is incomplete
+		panic(http.ErrAbortHandler)
+	}
+}
+
+//flushWriter flushes after every write, so each chunk reaches the client immediately
+type flushWriter struct {
+	io.Writer
+	flusher http.Flusher
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.Writer.Write(p)
+	f.flusher.Flush()
+	return n, err
+}
+
+//File serves the file at the path with support for Range, If-Range and If-Modified-Since requests.
+//The Content-Type is derived from the extension, missing files are rendered with StatusNotFound.
+func File(path string) HttpResponse {
+	return FileFS(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+//FileFS works like File, but opens the file with the given name from the fs.FS, e.g. an embed.FS
+func FileFS(fsys fs.FS, name string) HttpResponse {
+	return HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
+		serveFile(rw, r, fsys, name)
+	})
+}
+
+//Attachment makes the client download the response as a file with the given name instead of displaying it:
+//
+//	return Attachment("report.csv", File("/var/exports/42.csv"))
+//
+//Names with special characters are encoded as defined in RFC 6266, with an ASCII fallback for older clients.
+func Attachment(filename string, response HttpResponse) HttpResponse {
+	return WithHeaders(MapString{
+		ResponseHeaderContentDisposition: contentDisposition("attachment", filename),
+	}, response)
+}
+
+func contentDisposition(disposition, filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= 0x7F || r == '"' || r == '\\' || r == '/' {
+			return '_'
+		}
+		return r
+	}, filename)
+	header := disposition + `; filename="` + fallback + `"`
+	if fallback != filename {
+		header += "; filename*=UTF-8''" + encodeExtValue(filename)
+	}
+	return header
+}
+
+//encodeExtValue percent-encodes every byte, which is no attr-char of RFC 5987
+func encodeExtValue(value string) string {
+	const hex = "0123456789ABCDEF"
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			encoded.WriteByte(c)
+			continue
+		}
+		encoded.WriteByte('%')
+		encoded.WriteByte(hex[c>>4])
+		encoded.WriteByte(hex[c&0x0F])
+	}
+	return encoded.String()
+}
@@ -2,37 +2,120 @@ package there
 
 import (
 	"net/http"
+	"strings"
 )
 
 func (router *Router) ServeHTTP(rw http.ResponseWriter, request *http.Request) {
+	original := request
+	request = withRouter(request, router)
+	// net/http only removes the temporary files of its own request, not of the clone carrying the router
+	defer func() {
+		if request.MultipartForm != nil && original.MultipartForm == nil {
+			request.MultipartForm.RemoveAll()
+		}
+	}()
+	segments := splitUrl(request.URL.Path)
+	route, routeParams := router.tree.Find(segments, request.Method)
+
+	// answer HEAD with the GET route, net/http discards the body but keeps headers like Content-Length
+	if route == nil && request.Method == MethodHead && router.Configuration.HandleHead {
+		route, routeParams = router.tree.Find(segments, MethodGet)
+	}
 
 	httpRequest := NewHttpRequest(rw, request)
+	httpRequest.Body.maxMultipartMemory = router.Configuration.MaxMultipartMemory
+	httpRequest.Body.disallowUnknownFields = router.Configuration.DisallowUnknownFields
+	httpRequest.Body.maxBodyBytes = router.Configuration.MaxBodyBytes
 	var middlewares = make([]Middleware, 0)
 	middlewares = append(middlewares, router.globalMiddlewares...)
 
 	var endpoint Endpoint = nil
 
-	for _, current := range router.routes {
-		routeParams, ok := current.Path.Parse(request.URL.Path)
-		if ok && CheckArrayContains(current.Methods, request.Method) {
-			endpoint = current.Endpoint
-			middlewares = append(middlewares, current.Middlewares...)
-			routeParamReader := RouteParamReader(routeParams)
-			httpRequest.RouteParams = &routeParamReader
-			break
-		}
+	if route != nil {
+		endpoint = route.Endpoint
+		httpRequest.Body.maxBodyBytes = router.bodyLimit(route)
+		middlewares = append(middlewares, route.group.allMiddlewares()...)
+		middlewares = append(middlewares, route.Middlewares...)
+		routeParamReader := RouteParamReader(routeParams)
+		httpRequest.RouteParams = &routeParamReader
 	}
 
 	if endpoint == nil {
-		endpoint = router.Configuration.RouteNotFoundHandler
+		if allowed := router.allowedMethods(segments); len(allowed) == 0 {
+			endpoint = router.Configuration.RouteNotFoundHandler
+		} else if request.Method == MethodOptions && router.Configuration.HandleOptions {
+			endpoint = options(allowed)
+		} else {
+			endpoint = router.methodNotAllowed(allowed)
+		}
+	}
+
+	var body *limitedBody
+	if route != nil && request.Body != nil && request.Body != http.NoBody {
+		if limit := router.bodyLimit(route); limit > 0 && request.ContentLength > limit {
+			endpoint = router.Configuration.BodyTooLargeHandler
+		} else if limit > 0 {
+			body = &limitedBody{ReadCloser: request.Body, remaining: limit}
+			request.Body = body
+		}
 	}
 
 	var next HttpResponse = HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
-		endpoint(httpRequest).ServeHTTP(rw, r)
+		response := endpoint(httpRequest)
+		if body != nil && body.exceeded {
+			response = router.Configuration.BodyTooLargeHandler(httpRequest)
+		}
+		response.ServeHTTP(rw, r)
 	})
+	// every middleware is only invoked, once the previous one serves next. This way they run in the order
+	// they were registered and the remaining ones are skipped, if a middleware does not continue.
 	for i := len(middlewares) - 1; i >= 0; i-- {
-		middleware := middlewares[i]
-		next = middleware(httpRequest, next)
+		middleware, inner := middlewares[i], next
+		next = HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
+			middleware(httpRequest, inner).ServeHTTP(rw, r)
+		})
 	}
 	next.ServeHTTP(rw, request)
 }
+
+//bodyLimit returns the maximum amount of bytes, which can be read from the body of the route
+func (router *Router) bodyLimit(route *Route) int64 {
+	if route.maxBodyBytes != 0 {
+		return route.maxBodyBytes
+	}
+	return router.Configuration.MaxBodyBytes
+}
+
+//allowedMethods returns the methods the segments can be requested with,
+//including the ones the router answers on its own
+func (router *Router) allowedMethods(segments []string) []string {
+	methods := router.tree.AllowedMethods(segments)
+	if len(methods) == 0 {
+		return methods
+	}
+	if router.Configuration.HandleHead && CheckArrayContains(methods, MethodGet) && !CheckArrayContains(methods, MethodHead) {
+		methods = append(methods, MethodHead)
+	}
+	if router.Configuration.HandleOptions && !CheckArrayContains(methods, MethodOptions) {
+		methods = append(methods, MethodOptions)
+	}
+	return sortMethods(methods)
+}
+
+//methodNotAllowed wraps the MethodNotAllowedHandler and sets the Allow header to the given methods
+func (router *Router) methodNotAllowed(allowed []string) Endpoint {
+	return func(request HttpRequest) HttpResponse {
+		return WithHeaders(MapString{
+			ResponseHeaderAllow: strings.Join(allowed, ", "),
+		}, router.Configuration.MethodNotAllowedHandler(request))
+	}
+}
+
+//options answers an OPTIONS request with the given methods in the Allow header
+func options(allowed []string) Endpoint {
+	return func(request HttpRequest) HttpResponse {
+		return WithHeaders(MapString{
+			ResponseHeaderAllow: strings.Join(allowed, ", "),
+		}, Status(StatusNoContent))
+	}
+}
@@ -0,0 +1,69 @@
+package there
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+//RouteInfo is a read-only description of a registered Route
+type RouteInfo struct {
+	Name    string   `json:"name,omitempty"`
+	Methods []string `json:"methods"`
+	Path    string   `json:"path"`
+	Params  []string `json:"params"`
+	//Middlewares is the amount of group and route middlewares, global middlewares are not included
+	Middlewares int  `json:"middlewares"`
+	IgnoreCase  bool `json:"ignoreCase,omitempty"`
+}
+
+//Routes returns a description of every registered Route, sorted by path and methods
+func (router *Router) Routes() []RouteInfo {
+	infos := make([]RouteInfo, 0, len(router.routes))
+	for _, route := range router.routes {
+		methods := sortMethods(append([]string{}, route.Methods...))
+		infos = append(infos, RouteInfo{
+			Name:        route.Name,
+			Methods:     methods,
+			Path:        route.Path.ToString(),
+			Params:      route.Path.paramNames(),
+			Middlewares: len(route.group.allMiddlewares()) + len(route.Middlewares),
+			IgnoreCase:  route.Path.ignoreCase,
+		})
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Path != infos[j].Path {
+			return infos[i].Path < infos[j].Path
+		}
+		return strings.Join(infos[i].Methods, ",") < strings.Join(infos[j].Methods, ",")
+	})
+	return infos
+}
+
+//PrintRoutes writes the Routes as an aligned table, e.g. to log them at startup
+func (router *Router) PrintRoutes(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, err := fmt.Fprintln(tw, "METHODS\tPATH\tNAME\tMIDDLEWARES")
+	if err != nil {
+		return err
+	}
+	for _, info := range router.Routes() {
+		path := info.Path
+		if info.IgnoreCase {
+			path += " *IgnoreCase"
+		}
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", strings.Join(info.Methods, ","), path, info.Name, info.Middlewares)
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+//RoutesJson returns the Routes as indented JSON, which can be checked in and diffed to catch accidental route changes
+func (router *Router) RoutesJson() ([]byte, error) {
+	return json.MarshalIndent(router.Routes(), "", "  ")
+}
@@ -0,0 +1,55 @@
+package there
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func createInspectRouter() *Router {
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+	middleware := func(request HttpRequest, next HttpResponse) HttpResponse {
+		return next
+	}
+	router := NewRouter()
+	users := router.Group("/users").UseGroup(middleware)
+	users.Handle("/:id<int>", handler, MethodPut, MethodGet).With(middleware).Name("user.show")
+	router.Get("/Home", handler).IgnoreCase()
+	router.Post("/files/:bucket/*path", handler)
+	return router
+}
+
+func TestRouter_Routes(t *testing.T) {
+	want := []RouteInfo{
+		{Methods: []string{MethodGet}, Path: "/Home", Params: []string{}, IgnoreCase: true},
+		{Methods: []string{MethodPost}, Path: "/files/:bucket/*path", Params: []string{"bucket", "path"}},
+		{Name: "user.show", Methods: []string{MethodGet, MethodPut}, Path: "/users/:id<int>", Params: []string{"id"}, Middlewares: 2},
+	}
+	if got := createInspectRouter().Routes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Routes() = %v, want %v", got, want)
+	}
+}
+
+func TestRouter_PrintRoutes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := createInspectRouter().PrintRoutes(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "METHODS  PATH                  NAME       MIDDLEWARES\n" +
+		"GET      /Home *IgnoreCase                0\n" +
+		"POST     /files/:bucket/*path             0\n" +
+		"GET,PUT  /users/:id<int>       user.show  2\n"
+	AssertEquals(t, buf.String(), want)
+}
+
+func TestRouter_RoutesJson(t *testing.T) {
+	data, err := createInspectRouter().RoutesJson()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte(`"name": "user.show"`)) || !bytes.Contains(data, []byte(`"ignoreCase": true`)) {
+		t.Errorf("RoutesJson() = %s", data)
+	}
+}
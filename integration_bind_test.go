@@ -0,0 +1,138 @@
+package there_test
+
+import (
+	"encoding/json"
+	"errors"
+	. "github.com/Gebes/there/v2"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindFilter struct {
+	ID      int           `param:"id"`
+	Page    *int          `query:"page"`
+	Missing *int          `query:"missing"`
+	Tags    []string      `query:"tag"`
+	Active  bool          `query:"active"`
+	Since   time.Time     `query:"since" layout:"2006-01-02"`
+	Timeout time.Duration `query:"timeout"`
+	Tenant  string        `header:"X-Tenant"`
+	Form    struct {
+		Name  string  `form:"name"`
+		Score float64 `form:"score"`
+	}
+}
+
+func bindRequest(t *testing.T, target string, headers MapString, form url.Values, dest any) error {
+	var bindErr error
+	router := NewRouter()
+	router.Post("/users/:id", func(request HttpRequest) HttpResponse {
+		bindErr = request.Bind(dest)
+		return Status(StatusOK)
+	})
+
+	request := httptest.NewRequest(MethodPost, target, strings.NewReader(form.Encode()))
+	request.Header.Set(RequestHeaderContentType, ContentTypeApplicationXDashWwwDashFormDashUrlencoded)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	return bindErr
+}
+
+func TestBind(t *testing.T) {
+	var filter bindFilter
+	err := bindRequest(t, "/users/42?page=3&tag=a&tag=b&active=true&since=2022-05-01&timeout=1m30s",
+		MapString{"x-tenant": "acme"},
+		url.Values{"name": {"Hannes"}, "score": {"9.5"}},
+		&filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := 3
+	want := bindFilter{
+		ID:      42,
+		Page:    &page,
+		Tags:    []string{"a", "b"},
+		Active:  true,
+		Since:   time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC),
+		Timeout: 90 * time.Second,
+		Tenant:  "acme",
+	}
+	want.Form.Name = "Hannes"
+	want.Form.Score = 9.5
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("Bind() = %+v, want %+v", filter, want)
+	}
+}
+
+func TestBindError(t *testing.T) {
+	var filter bindFilter
+	err := bindRequest(t, "/users/abc?page=x&active=true", nil, url.Values{"score": {"high"}}, &filter)
+
+	var bindErr BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Bind() error = %v, want BindError", err)
+	}
+	var fields []string
+	for _, field := range bindErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	AssertEquals(t, strings.Join(fields, ","), "ID,Page,Form.Score")
+	if !filter.Active {
+		t.Error("valid fields should be bound despite errors")
+	}
+
+	if err = bindRequest(t, "/users/1", nil, nil, filter); err == nil {
+		t.Error("Bind() into a non pointer should fail")
+	}
+}
+
+type validatedUser struct {
+	Name    string `json:"name" validate:"required,min=3"`
+	Address struct {
+		City string `json:"city" validate:"required"`
+	} `json:"address"`
+}
+
+func TestBindJsonValidation(t *testing.T) {
+	router := NewRouter()
+	router.Post("/users", func(request HttpRequest) HttpResponse {
+		var user validatedUser
+		err := request.Body.BindJson(&user)
+		var validationErrors ValidationErrors
+		if errors.As(err, &validationErrors) {
+			return ValidationFailed(validationErrors)
+		}
+		if err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		return Status(StatusOK)
+	})
+
+	request := httptest.NewRequest(MethodPost, "/users", strings.NewReader(`{"name": "Al"}`))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusUnprocessableEntity)
+	}
+	var body struct {
+		Fields map[string][]string `json:"fields"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"name":         {"must have at least 3 characters"},
+		"address.city": {"is required"},
+	}
+	if !reflect.DeepEqual(body.Fields, want) {
+		t.Errorf("fields = %v, want %v", body.Fields, want)
+	}
+}
@@ -0,0 +1,111 @@
+package there_test
+
+import (
+	. "github.com/Gebes/there/v2"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+//unknownLengthReader hides the length of the body, so the limit is only hit while reading
+type unknownLengthReader struct {
+	io.Reader
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.MaxBodyBytes = 8
+	echo := func(request HttpRequest) HttpResponse {
+		body, err := request.Body.ToString()
+		if err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		return String(StatusOK, body)
+	}
+	router.Post("/small", echo)
+	router.Post("/large", echo).MaxBodyBytes(64)
+	router.Post("/unlimited", echo).MaxBodyBytes(-1)
+
+	tests := []struct {
+		name  string
+		route string
+		body  io.Reader
+		want  int
+	}{
+		{name: "within limit", route: "/small", body: strings.NewReader("12345678"), want: StatusOK},
+		{name: "content length exceeds", route: "/small", body: strings.NewReader("123456789"), want: StatusRequestEntityTooLarge},
+		{name: "read exceeds", route: "/small", body: unknownLengthReader{strings.NewReader("123456789")}, want: StatusRequestEntityTooLarge},
+		{name: "route override", route: "/large", body: strings.NewReader(strings.Repeat("a", 64)), want: StatusOK},
+		{name: "route override exceeds", route: "/large", body: unknownLengthReader{strings.NewReader(strings.Repeat("a", 65))}, want: StatusRequestEntityTooLarge},
+		{name: "disabled", route: "/unlimited", body: strings.NewReader(strings.Repeat("a", 1000)), want: StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(MethodPost, tt.route, tt.body))
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	router := NewRouter()
+	router.Post("/", func(request HttpRequest) HttpResponse {
+		var user simpleUser
+		if err := request.Body.BindJson(&user); err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		return String(StatusOK, user.Name)
+	})
+	body := `{"Name": "Hannes", "Age": 3}`
+
+	AssertEquals(t, readStringBody(router, t, MethodPost, "/", strings.NewReader(body)), "Hannes")
+
+	router.Configuration.DisallowUnknownFields = true
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(MethodPost, "/", strings.NewReader(body)))
+	if recorder.Code != StatusBadRequest {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusBadRequest)
+	}
+}
+
+func TestBindRejectsTrailingData(t *testing.T) {
+	router := NewRouter()
+	router.Post("/json", func(request HttpRequest) HttpResponse {
+		var user simpleUser
+		if err := request.Body.BindJson(&user); err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		return String(StatusOK, user.Name)
+	})
+	router.Post("/xml", func(request HttpRequest) HttpResponse {
+		var user simpleUser
+		if err := request.Body.BindXml(&user); err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		return String(StatusOK, user.Name)
+	})
+
+	tests := []struct {
+		route string
+		body  string
+		code  int
+	}{
+		{"/json", "{\"Name\": \"Hannes\"}\n", StatusOK},
+		{"/json", `{"Name": "Hannes"} trailing garbage`, StatusBadRequest},
+		{"/json", `{"Name": "Hannes"}{"Name": "Other"}`, StatusBadRequest},
+		{"/xml", "<user><Name>Hannes</Name></user>\n<!-- comment -->\n", StatusOK},
+		{"/xml", `<user><Name>Hannes</Name></user>trailing`, StatusBadRequest},
+		{"/xml", `<user><Name>Hannes</Name></user><user></user>`, StatusBadRequest},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(MethodPost, test.route, strings.NewReader(test.body)))
+		if recorder.Code != test.code {
+			t.Errorf("%s %q: status = %d, want %d", test.route, test.body, recorder.Code, test.code)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package there_test
+
+import (
+	"encoding/base64"
+	"errors"
+	. "github.com/Gebes/there/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func cookieRouter(keys ...string) *Router {
+	router := NewRouter()
+	for _, key := range keys {
+		router.Configuration.CookieKeys = append(router.Configuration.CookieKeys, []byte(key))
+	}
+	router.Get("/set", func(request HttpRequest) HttpResponse {
+		cookies := []*http.Cookie{{Name: "session", Value: "user=42; admin", Path: "/", HttpOnly: true}}
+		switch request.Params.GetDefault("mode", "") {
+		case "signed":
+			return WithSignedCookies(cookies, Status(StatusOK))
+		case "encrypted":
+			return WithEncryptedCookies(cookies, Status(StatusOK))
+		}
+		return WithCookies([]*http.Cookie{{Name: "theme", Value: "dark"}}, Status(StatusOK))
+	})
+	router.Get("/get", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		var value string
+		var err error
+		switch request.Params.GetDefault("mode", "") {
+		case "signed":
+			value, err = request.SignedCookie("session")
+		case "encrypted":
+			value, err = request.EncryptedCookie("session")
+		default:
+			value = request.Cookies.GetDefault("theme", "light")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return String(StatusOK, value), nil
+	}).Endpoint())
+	return router
+}
+
+//roundTrip sets the cookie with the first router and reads it with the second one
+func roundTrip(t *testing.T, set, get *Router, mode string, tamper func(string) string) *httptest.ResponseRecorder {
+	recorder := serve(set, MethodGet, "/set?mode="+mode)
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies, want 1", len(cookies))
+	}
+	request := httptest.NewRequest(MethodGet, "/get?mode="+mode, nil)
+	request.AddCookie(&http.Cookie{Name: cookies[0].Name, Value: tamper(cookies[0].Value)})
+	recorder = httptest.NewRecorder()
+	get.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func unchanged(value string) string {
+	return value
+}
+
+func TestCookies(t *testing.T) {
+	router := cookieRouter()
+	recorder := serve(router, MethodGet, "/set")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderSetCookie), "theme=dark")
+	AssertEquals(t, roundTrip(t, router, router, "", unchanged).Body.String(), "dark")
+	AssertEquals(t, serve(router, MethodGet, "/get").Body.String(), "light")
+
+	request := httptest.NewRequest(MethodGet, "/", nil)
+	request.Header.Set(RequestHeaderCookie, "page=3; tag=a; tag=b")
+	httpRequest := NewHttpRequest(httptest.NewRecorder(), request)
+	if page, err := httpRequest.Cookies.GetInt("page"); err != nil || page != 3 {
+		t.Errorf("page = %d, %v, want 3", page, err)
+	}
+	tags, _ := httpRequest.Cookies.GetSlice("tag")
+	AssertEquals(t, strings.Join(tags, ","), "a,b")
+}
+
+func TestSignedAndEncryptedCookies(t *testing.T) {
+	router := cookieRouter("current-secret-with-enough-entropy")
+	for _, mode := range []string{"signed", "encrypted"} {
+		recorder := roundTrip(t, router, router, mode, unchanged)
+		if recorder.Code != StatusOK {
+			t.Errorf("%s: status = %d, want %d", mode, recorder.Code, StatusOK)
+		}
+		AssertEquals(t, recorder.Body.String(), "user=42; admin")
+
+		recorder = roundTrip(t, router, router, mode, func(value string) string {
+			if value[0] == 'A' {
+				return "B" + value[1:]
+			}
+			return "A" + value[1:]
+		})
+		if recorder.Code != StatusBadRequest || !strings.Contains(recorder.Body.String(), ErrCookieInvalid.Error()) {
+			t.Errorf("%s: tampered cookie was accepted: %d %s", mode, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	set := serve(router, MethodGet, "/set?mode=encrypted").Result().Cookies()[0]
+	if strings.Contains(set.Value, "user") {
+		t.Errorf("encrypted cookie %q contains the plain value", set.Value)
+	}
+	AssertEquals(t, set.Path, "/")
+	if !set.HttpOnly {
+		t.Error("the attributes of the cookie should be kept")
+	}
+}
+
+func TestCookieKeyRotation(t *testing.T) {
+	old := cookieRouter("old-secret-with-enough-entropy")
+	rotated := cookieRouter("new-secret-with-enough-entropy", "old-secret-with-enough-entropy")
+	other := cookieRouter("other-secret-with-enough-entropy")
+
+	for _, mode := range []string{"signed", "encrypted"} {
+		AssertEquals(t, roundTrip(t, old, rotated, mode, unchanged).Body.String(), "user=42; admin")
+		if recorder := roundTrip(t, rotated, other, mode, unchanged); recorder.Code == StatusOK {
+			t.Errorf("%s: a cookie of an unknown key was accepted", mode)
+		}
+	}
+
+	request := NewHttpRequest(httptest.NewRecorder(), httptest.NewRequest(MethodGet, "/", nil))
+	if _, err := request.SignedCookie("session"); !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("err = %v, want %v", err, http.ErrNoCookie)
+	}
+}
+
+func TestSignedCookieCannotBeRenamed(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.CookieKeys = [][]byte{[]byte("current-secret-with-enough-entropy")}
+	router.Get("/set", func(request HttpRequest) HttpResponse {
+		return WithSignedCookies([]*http.Cookie{{Name: "a", Value: "b|c"}}, Status(StatusOK))
+	})
+	router.Get("/get", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		value, err := request.SignedCookie("a|b")
+		if err != nil {
+			return nil, err
+		}
+		return String(StatusOK, value), nil
+	}).Endpoint())
+
+	//the signature of name "a" and value "b|c" must not be valid for name "a|b" and value "c"
+	signed := serve(router, MethodGet, "/set").Result().Cookies()[0]
+	_, signature, _ := strings.Cut(signed.Value, ".")
+	request := httptest.NewRequest(MethodGet, "/get", nil)
+	request.AddCookie(&http.Cookie{Name: "a|b", Value: base64.RawURLEncoding.EncodeToString([]byte("c")) + "." + signature})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != StatusBadRequest {
+		t.Errorf("a cookie signed as %q was accepted as \"a|b\": %d %s", signed.Name, recorder.Code, recorder.Body.String())
+	}
+}
@@ -0,0 +1,165 @@
+package there_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "github.com/Gebes/there/v2"
+	"github.com/Gebes/there/v2/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestErrorRenderer(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.ErrorRenderer = func(request *http.Request, code int, err error) HttpResponse {
+		return String(code, fmt.Sprintf("%d %s", code, err))
+	}
+	router.Configuration.MaxBodyBytes = 4
+	router.Use(middlewares.Recoverer)
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		panic("boom")
+	})
+	router.Post("/upload", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	})
+
+	AssertEquals(t, serve(router, MethodGet, "/").Body.String(), "500 boom")
+	AssertEquals(t, serve(router, MethodGet, "/missing").Body.String(), "404 could not find route GET /missing")
+	AssertEquals(t, serve(router, MethodDelete, "/").Body.String(), "405 method DELETE is not allowed for route /")
+
+	request := httptest.NewRequest(MethodPost, "/upload", strings.NewReader("too large"))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	AssertEquals(t, recorder.Body.String(), "413 "+ErrBodyTooLarge.Error())
+}
+
+func TestProblemDetailsRenderer(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.ErrorRenderer = ProblemDetailsRenderer
+	router.Get("/custom", func(request HttpRequest) HttpResponse {
+		return Error(StatusConflict, fmt.Errorf("saving: %w", ProblemDetails{
+			Type:       "https://example.com/problems/out-of-stock",
+			Title:      "Out of stock",
+			Extensions: Map{"item": "42"},
+		}))
+	})
+
+	recorder := serve(router, MethodGet, "/missing")
+	if recorder.Code != StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNotFound)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeApplicationProblemPlusJson)
+	AssertEquals(t, recorder.Body.String(), `{"detail":"could not find route GET /missing","instance":"/missing","status":404,"title":"Not Found","type":"about:blank"}`)
+
+	recorder = serve(router, MethodGet, "/custom")
+	if recorder.Code != StatusConflict {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusConflict)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	AssertEquals(t, fmt.Sprint(body["type"]), "https://example.com/problems/out-of-stock")
+	AssertEquals(t, fmt.Sprint(body["title"]), "Out of stock")
+	AssertEquals(t, fmt.Sprint(body["status"]), "409")
+	AssertEquals(t, fmt.Sprint(body["instance"]), "/custom")
+	AssertEquals(t, fmt.Sprint(body["item"]), "42")
+}
+
+func TestValidationErrorRenderers(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.ErrorRenderer = NegotiatingErrorRenderer
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		return ValidationFailed(Validate(&struct {
+			Name string `json:"name" validate:"required"`
+			Age  int    `json:"age" validate:"min=18"`
+		}{}).(ValidationErrors))
+	})
+
+	request := httptest.NewRequest(MethodGet, "/", nil)
+	request.Header.Set(RequestHeaderAccept, ContentTypeApplicationXml)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	AssertEquals(t, recorder.Body.String(), `<error><message>validation failed: name is required; age must be at least 18</message>`+
+		`<field name="name"><message>is required</message></field><field name="age"><message>must be at least 18</message></field></error>`)
+
+	router.Configuration.ErrorRenderer = ProblemDetailsRenderer
+	recorder = serve(router, MethodGet, "/")
+	if recorder.Code != StatusUnprocessableEntity {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusUnprocessableEntity)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeApplicationProblemPlusJson)
+	AssertEquals(t, recorder.Body.String(), `{"detail":"validation failed: name is required; age must be at least 18",`+
+		`"fields":{"age":["must be at least 18"],"name":["is required"]},"instance":"/","status":422,"title":"Unprocessable Entity","type":"about:blank"}`)
+}
+
+func TestProblemResponse(t *testing.T) {
+	problem := ProblemDetails{Title: "Teapot", Status: StatusTeapot}
+	recorder := httptest.NewRecorder()
+	Problem(problem).ServeHTTP(recorder, httptest.NewRequest(MethodGet, "/", nil))
+
+	if recorder.Code != StatusTeapot {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusTeapot)
+	}
+	AssertEquals(t, recorder.Body.String(), `{"status":418,"title":"Teapot","type":"about:blank"}`)
+	AssertEquals(t, problem.Error(), "Teapot")
+	if !errors.As(fmt.Errorf("wrapped: %w", problem), &ProblemDetails{}) {
+		t.Error("ProblemDetails should be usable as error")
+	}
+}
+
+var errNotFound = errors.New("item not found")
+
+type teapotError struct{}
+
+func (teapotError) Error() string   { return "short and stout" }
+func (teapotError) StatusCode() int { return StatusTeapot }
+
+func TestErrorEndpoint(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.ErrorMapper = DefaultErrorMapper.Is(errNotFound, StatusNotFound)
+	router.Get("/ok", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		return String(StatusOK, "ok"), nil
+	}).Endpoint())
+	router.Get("/sentinel", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		return nil, fmt.Errorf("loading item: %w", errNotFound)
+	}).Endpoint())
+	router.Get("/coder", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		return nil, fmt.Errorf("brewing: %w", teapotError{})
+	}).Endpoint())
+	router.Get("/param", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		_, err := request.Params.GetInt("page")
+		return nil, err
+	}).Endpoint())
+	router.Get("/validation", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		return nil, Validate(&struct {
+			Name string `json:"name" validate:"required"`
+		}{})
+	}).Endpoint())
+	router.Get("/unknown", ErrorEndpoint(func(request HttpRequest) (HttpResponse, error) {
+		return nil, errors.New("database unavailable")
+	}).Endpoint())
+
+	tests := []struct {
+		route string
+		code  int
+		body  string
+	}{
+		{"/ok", StatusOK, "ok"},
+		{"/sentinel", StatusNotFound, `{"error":"loading item: item not found"}`},
+		{"/coder", StatusTeapot, `{"error":"brewing: short and stout"}`},
+		{"/param", StatusBadRequest, `{"error":"param is missing: \"page\""}`},
+		{"/validation", StatusUnprocessableEntity, `{"error":"validation failed: name is required","fields":{"name":["is required"]}}`},
+		{"/unknown", StatusInternalServerError, `{"error":"database unavailable"}`},
+	}
+	for _, test := range tests {
+		recorder := serve(router, MethodGet, test.route)
+		if recorder.Code != test.code {
+			t.Errorf("%s: status = %d, want %d", test.route, recorder.Code, test.code)
+		}
+		AssertEquals(t, recorder.Body.String(), test.body)
+	}
+}
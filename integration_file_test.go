@@ -0,0 +1,110 @@
+package there_test
+
+import (
+	. "github.com/Gebes/there/v2"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestStream(t *testing.T) {
+	reader := &closeRecorder{Reader: strings.NewReader(strings.Repeat("id,name\n", 10000))}
+	router := NewRouter()
+	router.Get("/export", func(request HttpRequest) HttpResponse {
+		return Stream(StatusOK, ContentTypeTextCsv, reader)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	response, err := server.Client().Get(server.URL + "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.TransferEncoding) != 1 || response.TransferEncoding[0] != "chunked" {
+		t.Errorf("transfer encoding = %v, want chunked", response.TransferEncoding)
+	}
+	AssertEquals(t, response.Header.Get(ResponseHeaderContentType), ContentTypeTextCsv)
+	if len(body) != 80000 {
+		t.Errorf("body has %d bytes, want %d", len(body), 80000)
+	}
+	if !reader.closed {
+		t.Error("the reader should be closed")
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.json")
+	if err := os.WriteFile(path, []byte(`{"id":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter()
+	router.Get("/file", func(request HttpRequest) HttpResponse {
+		return File(path)
+	})
+	router.Get("/missing", func(request HttpRequest) HttpResponse {
+		return File(filepath.Join(dir, "missing.json"))
+	})
+	router.Get("/fs", func(request HttpRequest) HttpResponse {
+		return Attachment("Übersicht 2022.txt", FileFS(fstest.MapFS{
+			"exports/summary.txt": {Data: []byte("0123456789")},
+		}, "exports/summary.txt"))
+	})
+
+	recorder := serve(router, MethodGet, "/file")
+	AssertEquals(t, recorder.Body.String(), `{"id":1}`)
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeApplicationJson)
+	lastModified := recorder.Header().Get(ResponseHeaderLastModified)
+
+	request := httptest.NewRequest(MethodGet, "/file", nil)
+	request.Header.Set(RequestHeaderRange, "bytes=1-4")
+	request.Header.Set(RequestHeaderIfRange, lastModified)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != StatusPartialContent {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusPartialContent)
+	}
+	AssertEquals(t, recorder.Body.String(), `"id"`)
+
+	request.Header.Set(RequestHeaderIfRange, "Mon, 02 Jan 2006 15:04:05 GMT")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != StatusOK {
+		t.Errorf("outdated If-Range: status = %d, want %d", recorder.Code, StatusOK)
+	}
+
+	if recorder = serve(router, MethodGet, "/missing"); recorder.Code != StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNotFound)
+	}
+
+	recorder = serve(router, MethodGet, "/fs")
+	AssertEquals(t, recorder.Body.String(), "0123456789")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeTextPlain)
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentDisposition), `attachment; filename="_bersicht 2022.txt"; filename*=UTF-8''%C3%9Cbersicht%202022.txt`)
+}
+
+func TestAttachmentAsciiName(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	Attachment("report.csv", String(StatusOK, "a,b")).ServeHTTP(recorder, httptest.NewRequest(MethodGet, "/", nil))
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentDisposition), `attachment; filename="report.csv"`)
+}
@@ -0,0 +1,108 @@
+package there_test
+
+import (
+	"bytes"
+	. "github.com/Gebes/there/v2"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func multipartRequest(t *testing.T, fields MapString, files map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, name+".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = part.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	request := httptest.NewRequest(MethodPost, "/upload", body)
+	request.Header.Set(RequestHeaderContentType, writer.FormDataContentType())
+	return request
+}
+
+func TestMultipartUpload(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "saved.txt")
+
+	router := NewRouter()
+	router.Configuration.MaxMultipartMemory = 1 // spill every file to disk
+	router.Post("/upload", func(request HttpRequest) HttpResponse {
+		form, err := request.Body.Form()
+		if err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		file, err := request.Body.File("document")
+		if err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		defer file.Close()
+
+		content, err := ioutil.ReadAll(file)
+		if err != nil {
+			return Error(StatusInternalServerError, err)
+		}
+		if err = file.SaveTo(target); err != nil {
+			return Error(StatusInternalServerError, err)
+		}
+		if _, err = request.Body.File("missing"); err != http.ErrMissingFile {
+			return Error(StatusInternalServerError, "expected ErrMissingFile")
+		}
+		return String(StatusOK, form.GetDefault("title", "")+"|"+file.Filename+"|"+string(content))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, multipartRequest(t, MapString{"title": "Report"}, map[string]string{"document": "hello"}))
+
+	AssertEquals(t, recorder.Body.String(), "Report|document.txt|hello")
+	saved, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertEquals(t, string(saved), "hello")
+}
+
+func TestMultipartTemporaryFilesAreRemoved(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	router := NewRouter()
+	router.Configuration.MaxMultipartMemory = 1 // spill every file to disk
+	router.Post("/upload", func(request HttpRequest) HttpResponse {
+		file, err := request.Body.File("document")
+		if err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		defer file.Close()
+		entries, _ := os.ReadDir(tmp)
+		return String(StatusOK, strconv.Itoa(len(entries)))
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, multipartRequest(t, nil, map[string]string{"document": "hello world"}))
+	AssertEquals(t, recorder.Body.String(), "1")
+
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%d temporary files were left behind", len(entries))
+	}
+}
@@ -0,0 +1,109 @@
+package there_test
+
+import (
+	"errors"
+	. "github.com/Gebes/there/v2"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type negotiated struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func negotiateRouter() *Router {
+	router := NewRouter()
+	router.Configuration.Serializers["text/csv"] = func(data any) ([]byte, error) {
+		return []byte("name\n" + data.(negotiated).Name), nil
+	}
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		return Negotiate(StatusOK, negotiated{Name: "there"})
+	})
+	return router
+}
+
+func serveAccept(router *Router, accept string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(MethodGet, "/", nil)
+	if accept != "" {
+		request.Header.Set(RequestHeaderAccept, accept)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestNegotiate(t *testing.T) {
+	router := negotiateRouter()
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", ContentTypeApplicationJson, `{"name":"there"}`},
+		{"*/*", ContentTypeApplicationJson, `{"name":"there"}`},
+		{"application/xml", ContentTypeApplicationXml, `<negotiated><name>there</name></negotiated>`},
+		{"application/json;q=0.5, text/csv", "text/csv", "name\nthere"},
+		{"text/html, application/xml;q=0.9, */*;q=0.1", ContentTypeApplicationXml, `<negotiated><name>there</name></negotiated>`},
+		{"text/*", "text/csv", "name\nthere"},
+		{"application/json;q=0, application/x-msgpack;q=0, application/yaml;q=0, */*", ContentTypeApplicationXml, `<negotiated><name>there</name></negotiated>`},
+		{"application/yaml", ContentTypeApplicationYaml, "name: there\n"},
+		{"application/x-msgpack", ContentTypeApplicationXDashMsgpack, "\x81\xa4name\xa5there"},
+	}
+	for _, test := range tests {
+		recorder := serveAccept(router, test.accept)
+		if recorder.Code != StatusOK {
+			t.Errorf("Accept %q: status = %d, want %d", test.accept, recorder.Code, StatusOK)
+		}
+		AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), test.contentType)
+		AssertEquals(t, recorder.Body.String(), test.body)
+	}
+}
+
+func TestNegotiateNotAcceptable(t *testing.T) {
+	router := negotiateRouter()
+	for _, accept := range []string{"text/html", "image/*", "application/json;q=0, application/xml;q=0, text/*;q=0"} {
+		recorder := serveAccept(router, accept)
+		if recorder.Code != StatusNotAcceptable {
+			t.Errorf("Accept %q: status = %d, want %d", accept, recorder.Code, StatusNotAcceptable)
+		}
+		AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeApplicationJson)
+	}
+}
+
+func TestCustomJsonSerializer(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.Serializers[ContentTypeApplicationJson] = func(data any) ([]byte, error) {
+		return []byte("custom"), nil
+	}
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		return Json(StatusOK, Map{})
+	})
+	router.Get("/error", func(request HttpRequest) HttpResponse {
+		return Error(StatusBadRequest, errors.New("failed"))
+	})
+
+	AssertEquals(t, serve(router, MethodGet, "/").Body.String(), "custom")
+	AssertEquals(t, serve(router, MethodGet, "/error").Body.String(), "custom")
+}
+
+func TestErrorNegotiatesMediaType(t *testing.T) {
+	router := NewRouter()
+	browser := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	recorder := serveAccept(router, browser)
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeApplicationJson)
+
+	router.Configuration.ErrorRenderer = NegotiatingErrorRenderer
+	request := httptest.NewRequest(MethodGet, "/missing", nil)
+	request.Header.Set(RequestHeaderAccept, "application/xml")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	if recorder.Code != StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNotFound)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeApplicationXml)
+	if !strings.Contains(recorder.Body.String(), "could not find route") {
+		t.Errorf("body = %q, want the error message", recorder.Body.String())
+	}
+}
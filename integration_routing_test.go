@@ -0,0 +1,249 @@
+package there_test
+
+import (
+	. "github.com/Gebes/there/v2"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(router *Router, method, route string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, route, nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	router := NewRouter()
+	router.Get("/user/:id", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	})
+	router.Patch("/user/:id", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	})
+
+	recorder := serve(router, MethodPost, "/user/1")
+	if recorder.Code != StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusMethodNotAllowed)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderAllow), "GET, HEAD, PATCH, OPTIONS")
+
+	recorder = serve(router, MethodPost, "/post/1")
+	if recorder.Code != StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNotFound)
+	}
+}
+
+func TestMethodNotAllowedHandler(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.MethodNotAllowedHandler = func(request HttpRequest) HttpResponse {
+		return String(StatusMethodNotAllowed, "nope")
+	}
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	})
+
+	recorder := serve(router, MethodDelete, "/")
+	AssertEquals(t, recorder.Body.String(), "nope")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderAllow), "GET, HEAD, OPTIONS")
+}
+
+func TestAutomaticHead(t *testing.T) {
+	router := NewRouter()
+	router.Get("/user/:id", func(request HttpRequest) HttpResponse {
+		return WithHeaders(MapString{"X-Id": request.RouteParams.GetDefault("id", "")}, String(StatusOK, "body"))
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+	response, err := http.Head(server.URL + "/user/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != StatusOK {
+		t.Errorf("status = %d, want %d", response.StatusCode, StatusOK)
+	}
+	AssertEquals(t, response.Header.Get("X-Id"), "1")
+	AssertEquals(t, response.Header.Get(ResponseHeaderContentLength), "4")
+	AssertEquals(t, response.Header.Get(ResponseHeaderContentType), "text/plain; charset=utf-8")
+	AssertEquals(t, string(body), "")
+
+	router.Configuration.HandleHead = false
+	recorder := serve(router, MethodHead, "/user/1")
+	if recorder.Code != StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusMethodNotAllowed)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderAllow), "GET, OPTIONS")
+}
+
+func TestAutomaticOptions(t *testing.T) {
+	router := NewRouter()
+	router.Handle("/user/:id", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}, MethodPut, MethodDelete)
+
+	recorder := serve(router, MethodOptions, "/user/1")
+	if recorder.Code != StatusNoContent {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNoContent)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderAllow), "PUT, DELETE, OPTIONS")
+
+	recorder = serve(router, MethodOptions, "/post/1")
+	if recorder.Code != StatusNotFound {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNotFound)
+	}
+
+	router.Configuration.HandleOptions = false
+	recorder = serve(router, MethodOptions, "/user/1")
+	if recorder.Code != StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusMethodNotAllowed)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderAllow), "PUT, DELETE")
+}
+
+func TestWildcardRoute(t *testing.T) {
+	router := NewRouter()
+	router.Group("/static").Get("/*filepath", func(request HttpRequest) HttpResponse {
+		return String(StatusOK, request.RouteParams.GetDefault("filepath", ""))
+	})
+
+	AssertEquals(t, serve(router, MethodGet, "/static/js/app/main.js").Body.String(), "js/app/main.js")
+	AssertEquals(t, serve(router, MethodGet, "/static").Body.String(), "")
+
+	defer func() { recover() }()
+	router.Get("/static/*path", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	})
+	t.Errorf("did not panic")
+}
+
+func TestConstrainedRoute(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/me", func(request HttpRequest) HttpResponse {
+		return String(StatusOK, "me")
+	})
+	router.Get("/users/:id<int>", func(request HttpRequest) HttpResponse {
+		return String(StatusOK, "user "+request.RouteParams.GetDefault("id", ""))
+	})
+
+	AssertEquals(t, serve(router, MethodGet, "/users/me").Body.String(), "me")
+	AssertEquals(t, serve(router, MethodGet, "/users/7").Body.String(), "user 7")
+	if code := serve(router, MethodGet, "/users/you").Code; code != StatusNotFound {
+		t.Errorf("status = %d, want %d", code, StatusNotFound)
+	}
+}
+
+func TestGroupMiddleware(t *testing.T) {
+	trace := func(name string) Middleware {
+		return func(request HttpRequest, next HttpResponse) HttpResponse {
+			return HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Header().Add("X-Order", name)
+				next.ServeHTTP(rw, r)
+			})
+		}
+	}
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+
+	router := NewRouter()
+	router.Use(trace("global"))
+	admin := router.Group("/admin")
+	users := admin.Group("/users")
+	users.Get("/:id", handler).With(trace("route"))
+	admin.UseGroup(trace("admin"))
+	users.UseGroup(trace("users"))
+	router.Get("/public", handler)
+
+	recorder := serve(router, MethodGet, "/admin/users/1")
+	AssertEquals(t, strings.Join(recorder.Header().Values("X-Order"), ","), "global,admin,users,route")
+
+	recorder = serve(router, MethodGet, "/public")
+	AssertEquals(t, strings.Join(recorder.Header().Values("X-Order"), ","), "global")
+}
+
+func TestMount(t *testing.T) {
+	echo := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		_, _ = rw.Write([]byte(r.Method + " " + r.URL.Path))
+	})
+
+	module := NewRouter()
+	module.Get("/items/:id", func(request HttpRequest) HttpResponse {
+		return String(StatusOK, "item "+request.RouteParams.GetDefault("id", ""))
+	})
+
+	router := NewRouter()
+	router.Mount("/echo", echo)
+	router.MountKeepPrefix("/debug/pprof", echo)
+	router.Group("/modules").Mount("/shop", module)
+
+	AssertEquals(t, serve(router, MethodPost, "/echo/a/b").Body.String(), "POST /a/b")
+	AssertEquals(t, serve(router, MethodGet, "/echo/dir/").Body.String(), "GET /dir/")
+	AssertEquals(t, serve(router, MethodGet, "/echo").Body.String(), "GET /")
+	AssertEquals(t, serve(router, MethodGet, "/debug/pprof/heap").Body.String(), "GET /debug/pprof/heap")
+	AssertEquals(t, serve(router, MethodGet, "/modules/shop/items/3").Body.String(), "item 3")
+	if code := serve(router, MethodGet, "/modules/shop/unknown").Code; code != StatusNotFound {
+		t.Errorf("status = %d, want %d", code, StatusNotFound)
+	}
+}
+
+//TestMiddlewareOrder documents the order in which middlewares are invoked. Previously every middleware
+//was invoked eagerly before the response was served, the innermost one first: route,group,global,endpoint.
+func TestMiddlewareOrder(t *testing.T) {
+	var invoked []string
+	middleware := func(name string) Middleware {
+		return func(request HttpRequest, next HttpResponse) HttpResponse {
+			invoked = append(invoked, name)
+			return next
+		}
+	}
+	router := NewRouter()
+	router.Use(middleware("global"))
+	group := router.Group("/group")
+	group.UseGroup(middleware("group"))
+	group.Get("/", func(request HttpRequest) HttpResponse {
+		invoked = append(invoked, "endpoint")
+		return Status(StatusOK)
+	}).With(middleware("route"))
+
+	serve(router, MethodGet, "/group")
+	AssertEquals(t, strings.Join(invoked, ","), "global,group,route,endpoint")
+}
+
+func TestUseIsGlobalOnGroups(t *testing.T) {
+	router := NewRouter()
+	router.Group("/api").Get("/users", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}).Use(func(request HttpRequest, next HttpResponse) HttpResponse {
+		return WithHeaders(MapString{"X-Global": "true"}, next)
+	})
+
+	AssertEquals(t, serve(router, MethodGet, "/api/users").Header().Get("X-Global"), "true")
+	AssertEquals(t, serve(router, MethodGet, "/missing").Header().Get("X-Global"), "true")
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var invoked []string
+	router := NewRouter()
+	router.Use(func(request HttpRequest, next HttpResponse) HttpResponse {
+		invoked = append(invoked, "auth")
+		return Status(StatusUnauthorized)
+	})
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		invoked = append(invoked, "endpoint")
+		return Status(StatusOK)
+	}).With(func(request HttpRequest, next HttpResponse) HttpResponse {
+		invoked = append(invoked, "route")
+		return next
+	})
+
+	if code := serve(router, MethodGet, "/").Code; code != StatusUnauthorized {
+		t.Errorf("status = %d, want %d", code, StatusUnauthorized)
+	}
+	AssertEquals(t, strings.Join(invoked, ","), "auth")
+}
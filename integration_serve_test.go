@@ -2,6 +2,11 @@ package there
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -37,3 +42,82 @@ func TestServerTsl_Start(t *testing.T) {
 	}
 	time.Sleep(time.Millisecond * 50)
 }
+
+func TestServer_ListenWithSignals(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.ShutdownTimeout = time.Second
+
+	started := make(chan struct{})
+	var order []string
+	router.
+		OnStart(func() error {
+			close(started)
+			return nil
+		}).
+		OnShutdown(func(ctx context.Context) error {
+			order = append(order, "first")
+			return nil
+		}).
+		OnShutdown(func(ctx context.Context) error {
+			order = append(order, "second")
+			return nil
+		})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- router.ListenWithSignals(8082)
+	}()
+
+	<-started
+	time.Sleep(time.Millisecond * 10)
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Error("unexpected error:", err)
+		}
+	case <-time.After(time.Second * 2):
+		t.Fatal("router did not shut down")
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Error("unexpected hook order:", order)
+	}
+}
+
+func TestServer_OnStartError(t *testing.T) {
+	router := NewRouter()
+	router.OnStart(func() error {
+		return errors.New("database unavailable")
+	})
+	if err := router.Listen(8083); err == nil || err.Error() != "database unavailable" {
+		t.Error("unexpected error:", err)
+	}
+}
+
+func TestShutdownEndsEventStreams(t *testing.T) {
+	router := NewRouter()
+	router.Get("/events", func(request HttpRequest) HttpResponse {
+		return EventStream(make(chan Event))
+	})
+	go router.Listen(8084)
+	time.Sleep(time.Millisecond * 10)
+
+	response, err := http.Get("http://localhost:8084/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err = router.Shutdown(ctx); err != nil {
+		t.Errorf("shutdown with an open stream failed: %v", err)
+	}
+}
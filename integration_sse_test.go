@@ -0,0 +1,72 @@
+package there_test
+
+import (
+	"context"
+	. "github.com/Gebes/there/v2"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventStream(t *testing.T) {
+	router := NewRouter()
+	router.Get("/events", func(request HttpRequest) HttpResponse {
+		events := make(chan Event, 3)
+		events <- Event{ID: "1", Event: "greeting", Data: "hello\nthere"}
+		events <- Event{ID: "2", Retry: 3 * time.Second, Data: Map{"resumed": request.LastEventID()}}
+		events <- Event{Event: "bad\nname"}
+		close(events)
+		return EventStream(events)
+	})
+
+	request := httptest.NewRequest(MethodGet, "/events", nil)
+	request.Header.Set(RequestHeaderLastEventID, "0")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeTextEventDashStream)
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderCacheControl), "no-cache")
+	AssertEquals(t, recorder.Body.String(), "id: 1\nevent: greeting\ndata: hello\ndata: there\n\n"+
+		"id: 2\nretry: 3000\ndata: {\"resumed\":\"0\"}\n\n"+
+		"event: badname\n\n")
+}
+
+func TestEventStreamFuncStopsOnCancel(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.EventStreamKeepAlive = 5 * time.Millisecond
+	stopped := make(chan error, 1)
+	router.Get("/events", func(request HttpRequest) HttpResponse {
+		return EventStreamFunc(func(ctx context.Context, send func(event Event) error) error {
+			if err := send(Event{Data: "first"}); err != nil {
+				t.Error(err)
+			}
+			<-ctx.Done()
+			stopped <- send(Event{Data: "too late"})
+			return nil
+		})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request := httptest.NewRequest(MethodGet, "/events", nil).WithContext(ctx)
+	recorder := httptest.NewRecorder()
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		cancel()
+	}()
+	router.ServeHTTP(recorder, request)
+
+	if err := <-stopped; err == nil {
+		t.Error("send should fail after the client disconnected")
+	}
+	body := recorder.Body.String()
+	if !strings.HasPrefix(body, "data: first\n\n") {
+		t.Errorf("body = %q, want the first event", body)
+	}
+	if !strings.Contains(body, ": keep-alive\n\n") {
+		t.Errorf("body = %q, want a keep-alive comment", body)
+	}
+	if strings.Contains(body, "too late") {
+		t.Errorf("body = %q, events after the disconnect must be dropped", body)
+	}
+}
@@ -0,0 +1,148 @@
+package there_test
+
+import (
+	"embed"
+	. "github.com/Gebes/there/v2"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+//go:embed test/index.html
+var embeddedFiles embed.FS
+
+func staticFiles() fstest.MapFS {
+	modTime := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	return fstest.MapFS{
+		"index.html":          {Data: []byte("<h1>home</h1>"), ModTime: modTime},
+		"css/site.css":        {Data: []byte("body{}"), ModTime: modTime},
+		"docs/readme.txt":     {Data: []byte("0123456789"), ModTime: modTime},
+		"docs/a & b.txt":      {Data: []byte("escaped"), ModTime: modTime},
+		"docs/guide/img.png":  {Data: []byte("png"), ModTime: modTime},
+		"docs/guide/data.yml": {Data: []byte("a: b"), ModTime: modTime},
+	}
+}
+
+func TestStatic(t *testing.T) {
+	router := NewRouter()
+	router.Static("/assets", staticFiles())
+
+	recorder := serve(router, MethodGet, "/assets/css/site.css")
+	if recorder.Code != StatusOK {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusOK)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeTextCss)
+	AssertEquals(t, recorder.Body.String(), "body{}")
+
+	recorder = serve(router, MethodGet, "/assets/")
+	AssertEquals(t, recorder.Body.String(), "<h1>home</h1>")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeTextHtml)
+
+	recorder = serve(router, MethodGet, "/assets/docs")
+	if recorder.Code != StatusMovedPermanently {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusMovedPermanently)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderLocation), "/assets/docs/")
+
+	for _, path := range []string{"/assets/docs/", "/assets/missing.css", "/assets/../router.go"} {
+		if recorder = serve(router, MethodGet, path); recorder.Code != StatusNotFound {
+			t.Errorf("%s: status = %d, want %d", path, recorder.Code, StatusNotFound)
+		}
+	}
+}
+
+func TestStaticConditionalAndRange(t *testing.T) {
+	router := NewRouter()
+	router.Static("/", staticFiles())
+
+	request := httptest.NewRequest(MethodGet, "/docs/readme.txt", nil)
+	request.Header.Set(RequestHeaderRange, "bytes=2-5")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != StatusPartialContent {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusPartialContent)
+	}
+	AssertEquals(t, recorder.Body.String(), "2345")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentRange), "bytes 2-5/10")
+
+	request = httptest.NewRequest(MethodGet, "/docs/readme.txt", nil)
+	request.Header.Set(RequestHeaderIfModifiedSince, "Sun, 01 May 2022 12:00:00 GMT")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != StatusNotModified {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusNotModified)
+	}
+
+	recorder = serve(router, MethodHead, "/docs/readme.txt")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentLength), "10")
+	AssertEquals(t, recorder.Body.String(), "")
+}
+
+func TestStaticBrowseAndSinglePageApp(t *testing.T) {
+	router := NewRouter()
+	router.StaticWithConfiguration("/files", staticFiles(), StaticConfiguration{Browse: true})
+	router.StaticWithConfiguration("/app", staticFiles(), StaticConfiguration{
+		IndexFiles:    []string{"index.html"},
+		SinglePageApp: true,
+	})
+
+	body := serve(router, MethodGet, "/files/docs/").Body.String()
+	for _, entry := range []string{`<a href="../">`, `<a href="a%20&amp;%20b.txt">a &amp; b.txt</a>`, `<a href="guide/">guide/</a>`, `<a href="readme.txt">`} {
+		if !strings.Contains(body, entry) {
+			t.Errorf("listing %q does not contain %q", body, entry)
+		}
+	}
+	AssertEquals(t, serve(router, MethodGet, "/files/docs/guide/data.yml").Header().Get(ResponseHeaderContentType), ContentTypeApplicationYaml)
+
+	recorder := serve(router, MethodGet, "/app/users/42/settings")
+	if recorder.Code != StatusOK {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusOK)
+	}
+	AssertEquals(t, recorder.Body.String(), "<h1>home</h1>")
+	AssertEquals(t, serve(router, MethodGet, "/app/css/site.css").Body.String(), "body{}")
+}
+
+func TestStaticDirectoryAndEmbed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter()
+	router.Static("/dir", dir)
+	router.Static("/embed", embeddedFiles)
+
+	recorder := serve(router, MethodGet, "/dir/hello.txt")
+	AssertEquals(t, recorder.Body.String(), "hello")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeTextPlain)
+	if recorder.Header().Get(ResponseHeaderLastModified) == "" {
+		t.Error("files of a directory should have a Last-Modified header")
+	}
+
+	recorder = serve(router, MethodGet, "/embed/test/")
+	if recorder.Code != StatusOK || !strings.Contains(recorder.Body.String(), "{{.user}}") {
+		t.Errorf("status = %d, body = %q, want the embedded index.html", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestStaticInvokesNotFoundHandlerOnlyForMissingFiles(t *testing.T) {
+	router := NewRouter()
+	notFound := 0
+	router.Configuration.RouteNotFoundHandler = func(request HttpRequest) HttpResponse {
+		notFound++
+		return Status(StatusNotFound)
+	}
+	router.Static("/assets", staticFiles())
+
+	serve(router, MethodGet, "/assets/css/site.css")
+	serve(router, MethodGet, "/assets/")
+	if notFound != 0 {
+		t.Errorf("the not found handler was invoked %d times for existing files", notFound)
+	}
+	if code := serve(router, MethodGet, "/assets/missing.css").Code; code != StatusNotFound || notFound != 1 {
+		t.Errorf("status = %d, invocations = %d, want %d and 1", code, notFound, StatusNotFound)
+	}
+}
@@ -0,0 +1,86 @@
+package there_test
+
+import (
+	. "github.com/Gebes/there/v2"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func templateFiles() fstest.MapFS {
+	modTime := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	return fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`{{define "base"}}<title>{{block "title" .}}there{{end}}</title>{{template "nav" .}}<main>{{block "content" .}}{{end}}</main>{{end}}`), ModTime: modTime},
+		"partials/nav.html": {Data: []byte(`{{define "nav"}}<nav>{{shout "menu"}}</nav>{{end}}`), ModTime: modTime},
+		"users/show.html":   {Data: []byte(`{{template "base" .}}{{define "title"}}{{.Name}}{{end}}{{define "content"}}<a href="{{url "user.show" "id" .ID}}">{{.Name}}</a>{{end}}`), ModTime: modTime},
+		"home.html":         {Data: []byte(`{{template "base" .}}{{define "content"}}home{{end}}`), ModTime: modTime},
+	}
+}
+
+func TestHtmlTemplate(t *testing.T) {
+	router := NewRouter()
+	router.Templates(NewTemplateEngine(templateFiles()).Funcs(template.FuncMap{
+		"shout": strings.ToUpper,
+	}))
+	router.Get("/user/:id", func(request HttpRequest) HttpResponse {
+		return HtmlTemplate(StatusOK, "users/show", map[string]string{
+			"ID":   request.RouteParams.GetDefault("id", ""),
+			"Name": "<Hannes>",
+		})
+	}).Name("user.show")
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		return HtmlTemplate(StatusOK, "home.html", nil)
+	})
+	router.Get("/nav", func(request HttpRequest) HttpResponse {
+		return HtmlTemplate(StatusOK, "nav", nil)
+	})
+
+	recorder := serve(router, MethodGet, "/user/42")
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderContentType), ContentTypeTextHtml)
+	AssertEquals(t, recorder.Body.String(), `<title>&lt;Hannes&gt;</title><nav>MENU</nav><main><a href="/user/42">&lt;Hannes&gt;</a></main>`)
+	AssertEquals(t, serve(router, MethodGet, "/").Body.String(), `<title>there</title><nav>MENU</nav><main>home</main>`)
+	AssertEquals(t, serve(router, MethodGet, "/nav").Body.String(), `<nav>MENU</nav>`)
+}
+
+func TestTemplateEngineReload(t *testing.T) {
+	files := templateFiles()
+	engine := NewTemplateEngine(files).Funcs(NewRouter().TemplateFuncs()).Funcs(template.FuncMap{"shout": strings.ToUpper})
+	if err := engine.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var page strings.Builder
+	render := func() string {
+		page.Reset()
+		if err := engine.Render(&page, "home", nil); err != nil {
+			t.Fatal(err)
+		}
+		return page.String()
+	}
+	change := func() {
+		files["partials/nav.html"] = &fstest.MapFile{Data: []byte(`{{define "nav"}}changed{{end}}`), ModTime: time.Now()}
+	}
+
+	change()
+	AssertEquals(t, render(), `<title>there</title><nav>MENU</nav><main>home</main>`)
+
+	engine.Reload(true)
+	AssertEquals(t, render(), `<title>there</title>changed<main>home</main>`)
+}
+
+func TestTemplateEngineErrors(t *testing.T) {
+	files := templateFiles()
+	files["broken.html"] = &fstest.MapFile{Data: []byte(`{{if}}`)}
+	router := NewRouter()
+	router.Templates(NewTemplateEngine(files))
+	if err := router.Configuration.TemplateEngine.Load(); err == nil {
+		t.Error("loading a broken template should fail")
+	}
+
+	engine := NewTemplateEngine(templateFiles()).Funcs(NewRouter().TemplateFuncs()).Funcs(template.FuncMap{"shout": strings.ToUpper})
+	if err := engine.Render(&strings.Builder{}, "missing", nil); err == nil || !strings.Contains(err.Error(), "no template") {
+		t.Errorf("err = %v, want a missing template error", err)
+	}
+}
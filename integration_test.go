@@ -22,7 +22,7 @@ var (
 	}
 	sampleUser       = user{"Hannes", "A cool user"}
 	sampleSimpleUser = simpleUser{"Hannes"}
-	errorData        = map[any]any{}
+	errorData        = map[string]any{"channel": make(chan int)}
 	errorMarshal     = func(i any) ([]byte, error) {
 		return nil, errors.New("test")
 	}
@@ -370,6 +370,17 @@ func TestBytesResponse(t *testing.T) {
 	AssertEquals(t, "ab", res)
 }
 
+func TestHtmlWithFuncsResponse(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/:id", func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}).Name("user.show")
+	router.Get("/", func(request HttpRequest) HttpResponse {
+		return HtmlWithFuncs(StatusOK, "./test/url.html", router.TemplateFuncs(), "42")
+	})
+	AssertEquals(t, readStringBody(router, t, MethodGet, "/", nil), "/users/42")
+}
+
 func readStringBody(router *Router, t *testing.T, method, route string, body io.Reader) string {
 
 	request := httptest.NewRequest(method, route, body)
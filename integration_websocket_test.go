@@ -0,0 +1,269 @@
+package there_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	. "github.com/Gebes/there/v2"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+//webSocketClient is a minimal client, which sends masked frames
+type webSocketClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server, path string) *webSocketClient {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Write([]byte("GET " + path + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Origin: http://localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want %d", response.StatusCode, StatusSwitchingProtocols)
+	}
+	AssertEquals(t, response.Header.Get(ResponseHeaderSecWebSocketAccept), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+	return &webSocketClient{conn: conn, reader: reader}
+}
+
+func (c *webSocketClient) write(t *testing.T, fin bool, opcode byte, payload []byte) {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{first, 0x80 | byte(len(payload))}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *webSocketClient) read(t *testing.T) (byte, []byte) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, header[1]&0x7F)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		t.Fatal(err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+func TestWebSocket(t *testing.T) {
+	router := NewRouter()
+	closed := make(chan error, 1)
+	router.WebSocket("/rooms/:room", func(request HttpRequest, conn *WebSocketConn) {
+		room := request.RouteParams.GetDefault("room", "")
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				closed <- err
+				return
+			}
+			if err = conn.WriteMessage(messageType, append([]byte(room+": "), data...)); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := dialWebSocket(t, server, "/rooms/lobby")
+
+	client.write(t, true, WebSocketText, []byte("hello"))
+	opcode, payload := client.read(t)
+	if opcode != WebSocketText {
+		t.Errorf("opcode = %d, want %d", opcode, WebSocketText)
+	}
+	AssertEquals(t, string(payload), "lobby: hello")
+
+	client.write(t, false, WebSocketBinary, []byte("frag"))
+	client.write(t, true, 0x9, []byte("ping"))
+	client.write(t, true, 0x0, []byte("ment"))
+	opcode, payload = client.read(t)
+	if opcode != 0xA {
+		t.Errorf("opcode = %d, want a pong", opcode)
+	}
+	AssertEquals(t, string(payload), "ping")
+	opcode, payload = client.read(t)
+	if opcode != WebSocketBinary {
+		t.Errorf("opcode = %d, want %d", opcode, WebSocketBinary)
+	}
+	AssertEquals(t, string(payload), "lobby: fragment")
+
+	closePayload := []byte{0, 0}
+	binary.BigEndian.PutUint16(closePayload, WebSocketCloseGoingAway)
+	client.write(t, true, 0x8, append(closePayload, "bye"...))
+	opcode, payload = client.read(t)
+	if opcode != 0x8 || binary.BigEndian.Uint16(payload) != WebSocketCloseGoingAway {
+		t.Errorf("frame = %d %v, want the close frame echoed", opcode, payload)
+	}
+
+	var closeErr *WebSocketCloseError
+	if err := <-closed; !errors.As(err, &closeErr) || closeErr.Code != WebSocketCloseGoingAway || closeErr.Reason != "bye" {
+		t.Errorf("err = %v, want a WebSocketCloseError with code %d", err, WebSocketCloseGoingAway)
+	}
+}
+
+func TestWebSocketClosedByServer(t *testing.T) {
+	router := NewRouter()
+	router.WebSocket("/", func(request HttpRequest, conn *WebSocketConn) {
+		conn.WriteText("welcome")
+		conn.Close(WebSocketClosePolicyViolation, "go away")
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := dialWebSocket(t, server, "/")
+	_, payload := client.read(t)
+	AssertEquals(t, string(payload), "welcome")
+	opcode, payload := client.read(t)
+	if opcode != 0x8 || binary.BigEndian.Uint16(payload) != WebSocketClosePolicyViolation {
+		t.Errorf("frame = %d %v, want a close frame", opcode, payload)
+	}
+	AssertEquals(t, string(payload[2:]), "go away")
+	client.write(t, true, 0x8, payload[:2])
+}
+
+func TestWebSocketRejectsUnmaskedFrames(t *testing.T) {
+	router := NewRouter()
+	router.WebSocket("/", func(request HttpRequest, conn *WebSocketConn) {
+		conn.ReadMessage()
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := dialWebSocket(t, server, "/")
+	client.conn.Write([]byte{0x81, 0x02, 'h', 'i'})
+	opcode, payload := client.read(t)
+	if opcode != 0x8 || binary.BigEndian.Uint16(payload) != WebSocketCloseProtocolError {
+		t.Errorf("frame = %d %v, want a protocol error", opcode, payload)
+	}
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	router := NewRouter()
+	router.Use(func(request HttpRequest, next HttpResponse) HttpResponse {
+		if request.Params.GetDefault("token", "") != "secret" {
+			return Error(StatusUnauthorized, "unauthorized")
+		}
+		return next
+	})
+	router.WebSocket("/ws", func(request HttpRequest, conn *WebSocketConn) {
+		t.Error("the handler must not be called")
+	})
+
+	recorder := serve(router, MethodGet, "/ws")
+	if recorder.Code != StatusUnauthorized {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusUnauthorized)
+	}
+
+	request := httptest.NewRequest(MethodGet, "/ws?token=secret", nil)
+	request.Header.Set(RequestHeaderSecWebSocketVersion, "13")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != StatusBadRequest {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusBadRequest)
+	}
+
+	recorder = serve(router, MethodGet, "/ws?token=secret")
+	if recorder.Code != StatusUpgradeRequired {
+		t.Errorf("status = %d, want %d", recorder.Code, StatusUpgradeRequired)
+	}
+	AssertEquals(t, recorder.Header().Get(ResponseHeaderSecWebSocketVersion), "13")
+}
+
+func TestWebSocketHugeFrameWithoutLimit(t *testing.T) {
+	router := NewRouter()
+	result := make(chan error, 1)
+	router.WebSocket("/", func(request HttpRequest, conn *WebSocketConn) {
+		conn.SetReadLimit(0)
+		_, _, err := conn.ReadMessage()
+		result <- err
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	client := dialWebSocket(t, server, "/")
+	//a binary frame declaring 2^62 bytes, followed by the mask and a few bytes only
+	client.conn.Write([]byte{0x82, 0xFF, 0x40, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 4, 'h', 'i'})
+	client.conn.Close()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Error("reading a truncated frame should fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler did not return")
+	}
+}
+
+func TestWebSocketOrigin(t *testing.T) {
+	router := NewRouter()
+	router.WebSocket("/ws", func(request HttpRequest, conn *WebSocketConn) {
+		t.Error("the handler must not be called")
+	})
+	upgrade := func(origin string) int {
+		request := httptest.NewRequest(MethodGet, "http://example.com/ws", nil)
+		request.Header.Set(RequestHeaderConnection, "Upgrade")
+		request.Header.Set(RequestHeaderUpgrade, "websocket")
+		request.Header.Set(RequestHeaderSecWebSocketKey, "dGhlIHNhbXBsZSBub25jZQ==")
+		request.Header.Set(RequestHeaderSecWebSocketVersion, "13")
+		if origin != "" {
+			request.Header.Set(RequestHeaderOrigin, origin)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	//the recorder cannot be hijacked, so allowed upgrades fail afterwards with StatusInternalServerError
+	for origin, code := range map[string]int{
+		"":                        StatusInternalServerError,
+		"https://example.com":     StatusInternalServerError,
+		"https://evil.example":    StatusForbidden,
+		"https://example.com:444": StatusForbidden,
+	} {
+		if got := upgrade(origin); got != code {
+			t.Errorf("Origin %q: status = %d, want %d", origin, got, code)
+		}
+	}
+
+	router.Configuration.WebSocketCheckOrigin = func(request *http.Request) bool {
+		return request.Header.Get(RequestHeaderOrigin) == "https://app.example"
+	}
+	if got := upgrade("https://app.example"); got != StatusInternalServerError {
+		t.Errorf("status = %d, want the custom check to allow the origin", got)
+	}
+	if got := upgrade("https://example.com"); got != StatusForbidden {
+		t.Errorf("status = %d, want %d", got, StatusForbidden)
+	}
+}
@@ -0,0 +1,18 @@
+package middlewares
+
+import (
+	"errors"
+	. "github.com/Gebes/there/v2"
+)
+
+//BufferBody keeps the body in memory, so the following middlewares and the endpoint can read it any number of times.
+//Bodies exceeding the limit of the route are answered by RouterConfiguration.BodyTooLargeHandler.
+func BufferBody(request HttpRequest, next HttpResponse) HttpResponse {
+	if err := request.BufferBody(); err != nil {
+		if errors.Is(err, ErrBodyTooLarge) {
+			return request.Configuration().BodyTooLargeHandler(request)
+		}
+		return Error(StatusBadRequest, err)
+	}
+	return next
+}
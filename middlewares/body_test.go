@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	. "github.com/Gebes/there/v2"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBufferBody(t *testing.T) {
+	router := NewRouter()
+	router.Use(BufferBody)
+	router.Use(func(request HttpRequest, next HttpResponse) HttpResponse {
+		body, err := request.Body.ToString()
+		if err != nil || body != `{"Name":"Hannes"}` {
+			return Error(StatusUnauthorized, "signature mismatch")
+		}
+		return next
+	})
+	router.Post("/", func(request HttpRequest) HttpResponse {
+		var user struct{ Name string }
+		if err := request.Body.BindJson(&user); err != nil {
+			return Error(StatusBadRequest, err)
+		}
+		return String(StatusOK, user.Name)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(MethodPost, "/", strings.NewReader(`{"Name":"Hannes"}`)))
+	if recorder.Code != StatusOK || recorder.Body.String() != "Hannes" {
+		t.Fatalf("unexpected response %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	router.Configuration.MaxBodyBytes = 4
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(MethodPost, "/", strings.NewReader(`{"Name":"Hannes"}`)))
+	if recorder.Code != StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", recorder.Code, StatusRequestEntityTooLarge)
+	}
+}
+
+func TestBufferBodyLimitsUnmatchedRoutes(t *testing.T) {
+	router := NewRouter()
+	router.Configuration.MaxBodyBytes = 4
+	router.Configuration.BodyTooLargeHandler = func(request HttpRequest) HttpResponse {
+		return String(StatusRequestEntityTooLarge, "custom")
+	}
+	router.Use(BufferBody)
+
+	body := strings.NewReader("too large")
+	for _, request := range []*http.Request{
+		httptest.NewRequest(MethodPost, "/missing", body),
+		// without a Content-Length the limit applies while reading
+		httptest.NewRequest(MethodPost, "/missing", io.MultiReader(strings.NewReader("too large"))),
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		if recorder.Code != StatusRequestEntityTooLarge || recorder.Body.String() != "custom" {
+			t.Errorf("unexpected response %d %s", recorder.Code, recorder.Body.String())
+		}
+	}
+}
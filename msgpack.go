@@ -0,0 +1,106 @@
+package there
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+//MarshalMsgpack is the Serializer registered for ContentTypeApplicationXDashMsgpack.
+//The data is marshaled like by encoding/json, so json tags and json.Marshaler are respected.
+//Integers use the smallest msgpack integer type, other numbers are written as float 64.
+func MarshalMsgpack(data any) ([]byte, error) {
+	tree, err := jsonTree(data)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	writeMsgpack(&buffer, tree)
+	return buffer.Bytes(), nil
+}
+
+func writeMsgpack(buffer *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteByte(0xc0)
+	case bool:
+		if v {
+			buffer.WriteByte(0xc3)
+		} else {
+			buffer.WriteByte(0xc2)
+		}
+	case json.Number:
+		writeMsgpackNumber(buffer, v)
+	case string:
+		writeMsgpackHeader(buffer, len(v), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		buffer.WriteString(v)
+	case []any:
+		writeMsgpackHeader(buffer, len(v), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			writeMsgpack(buffer, item)
+		}
+	case orderedObject:
+		writeMsgpackHeader(buffer, len(v), 0x80, 15, 0, 0xde, 0xdf)
+		for _, field := range v {
+			writeMsgpack(buffer, field.key)
+			writeMsgpack(buffer, field.value)
+		}
+	}
+}
+
+//writeMsgpackHeader writes the type and length of a string, array or map.
+//Lengths up to fixMax are stored in the fix byte, the other formats store them in 8, 16 or 32 bits. A zero format is not available.
+func writeMsgpackHeader(buffer *bytes.Buffer, length int, fix byte, fixMax int, format8, format16, format32 byte) {
+	switch {
+	case length <= fixMax:
+		buffer.WriteByte(fix | byte(length))
+	case format8 != 0 && length <= math.MaxUint8:
+		buffer.Write([]byte{format8, byte(length)})
+	case length <= math.MaxUint16:
+		buffer.WriteByte(format16)
+		writeMsgpackUint(buffer, 2, uint64(length))
+	default:
+		buffer.WriteByte(format32)
+		writeMsgpackUint(buffer, 4, uint64(length))
+	}
+}
+
+//writeMsgpackUint writes the lowest size bytes of value in big endian
+func writeMsgpackUint(buffer *bytes.Buffer, size int, value uint64) {
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], value)
+	buffer.Write(data[8-size:])
+}
+
+func writeMsgpackNumber(buffer *bytes.Buffer, number json.Number) {
+	if i, err := strconv.ParseInt(string(number), 10, 64); err == nil {
+		switch {
+		case i >= 0 && i <= math.MaxInt8:
+			buffer.WriteByte(byte(i))
+		case i < 0 && i >= -32:
+			buffer.WriteByte(byte(int8(i)))
+		case i >= math.MinInt8 && i <= math.MaxInt8:
+			buffer.Write([]byte{0xd0, byte(int8(i))})
+		case i >= math.MinInt16 && i <= math.MaxInt16:
+			buffer.WriteByte(0xd1)
+			writeMsgpackUint(buffer, 2, uint64(uint16(i)))
+		case i >= math.MinInt32 && i <= math.MaxInt32:
+			buffer.WriteByte(0xd2)
+			writeMsgpackUint(buffer, 4, uint64(uint32(i)))
+		default:
+			buffer.WriteByte(0xd3)
+			writeMsgpackUint(buffer, 8, uint64(i))
+		}
+		return
+	}
+	if u, err := strconv.ParseUint(string(number), 10, 64); err == nil {
+		buffer.WriteByte(0xcf)
+		writeMsgpackUint(buffer, 8, u)
+		return
+	}
+	f, _ := number.Float64()
+	buffer.WriteByte(0xcb)
+	writeMsgpackUint(buffer, 8, math.Float64bits(f))
+}
@@ -1,6 +1,9 @@
 package there
 
 import (
+	"errors"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
@@ -12,6 +15,27 @@ type Path struct {
 type pathPart struct {
 	value    string
 	variable bool
+	//wildcard parts are variable and capture the remainder of the path
+	wildcard bool
+	//constraint is the raw constraint of a variable part, like int or [a-z]+
+	constraint string
+	matcher    *regexp.Regexp
+}
+
+//constraintTypes are the named constraints, which can be used instead of a regular expression
+var constraintTypes = map[string]string{
+	"int":   `-?[0-9]+`,
+	"uint":  `[0-9]+`,
+	"float": `-?[0-9]+(\.[0-9]+)?`,
+	"bool":  `true|false|1|0`,
+	"alpha": `[a-zA-Z]+`,
+	"alnum": `[a-zA-Z0-9]+`,
+	"uuid":  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+}
+
+//matches checks if the value satisfies the constraint of the part
+func (part pathPart) matches(value string) bool {
+	return part.matcher == nil || part.matcher.MatchString(value)
 }
 
 // ConstructPath returns the path to match.
@@ -20,20 +44,51 @@ func ConstructPath(pathString string, ignoreCase bool) Path {
 	parts := make([]pathPart, len(split))
 	for i, s := range split {
 		variable := false
+		wildcard := false
 		const variablePrefix = ":"
-		if strings.HasPrefix(s, variablePrefix) {
+		const wildcardPrefix = "*"
+		if strings.HasPrefix(s, wildcardPrefix) {
+			s = s[len(wildcardPrefix):]
+			if i != len(split)-1 {
+				panic(pathString + " has defined the wildcard \"" + s + "\" before the last segment")
+			}
+			variable = true
+			wildcard = true
+		} else if strings.HasPrefix(s, variablePrefix) {
 			s = s[len(variablePrefix):]
+			variable = true
+		}
+
+		constraint := ""
+		var matcher *regexp.Regexp
+		if start := strings.Index(s, "<"); variable && start != -1 && strings.HasSuffix(s, ">") {
+			constraint = s[start+1 : len(s)-1]
+			s = s[:start]
+			expression, ok := constraintTypes[constraint]
+			if !ok {
+				expression = constraint
+			}
+			var err error
+			matcher, err = regexp.Compile("^(?:" + expression + ")$")
+			if err != nil {
+				panic(pathString + " has an invalid constraint for the route param \"" + s + "\": " + err.Error())
+			}
+		}
+
+		if variable {
 			for _, part := range parts {
 				if part.variable && part.value == s {
 					panic(pathString + " has defined the route param \"" + s + "\" more than once")
 				}
 			}
-			variable = true
 		}
 
 		parts[i] = pathPart{
-			value:    s,
-			variable: variable,
+			value:      s,
+			variable:   variable,
+			wildcard:   wildcard,
+			constraint: constraint,
+			matcher:    matcher,
 		}
 	}
 
@@ -47,10 +102,15 @@ func ConstructPath(pathString string, ignoreCase bool) Path {
 func (p Path) ToString() string {
 	path := "/"
 	for i, part := range p.parts {
-		if part.variable {
+		if part.wildcard {
+			path += "*"
+		} else if part.variable {
 			path += ":"
 		}
 		path += part.value
+		if part.constraint != "" {
+			path += "<" + part.constraint + ">"
+		}
 		if i != len(p.parts)-1 {
 			path += "/"
 		}
@@ -71,6 +131,9 @@ func (p Path) Equals(toCompare Path) bool {
 	for i := 0; i < len(p.parts); i++ {
 		a := p.parts[i]
 		b := toCompare.parts[i]
+		if a.wildcard != b.wildcard || a.constraint != b.constraint {
+			return false
+		}
 		if !a.variable && !b.variable {
 			if (ignoreCase && strings.ToLower(a.value) != strings.ToLower(b.value)) ||
 				(!ignoreCase && a.value != b.value) {
@@ -89,7 +152,12 @@ func (p Path) Parse(route string) (map[string]string, bool) {
 
 	split := splitUrl(route)
 
-	if len(split) != len(p.parts) {
+	if p.hasWildcard() {
+		// the wildcard may capture an empty remainder
+		if len(split) < len(p.parts)-1 {
+			return nil, false
+		}
+	} else if len(split) != len(p.parts) {
 		return nil, false
 	}
 
@@ -97,8 +165,19 @@ func (p Path) Parse(route string) (map[string]string, bool) {
 
 	for i := 0; i < len(p.parts); i++ {
 		a := p.parts[i]
+		if a.wildcard {
+			remainder := strings.Join(split[i:], "/")
+			if !a.matches(remainder) {
+				return nil, false
+			}
+			params[a.value] = remainder
+			break
+		}
 		b := split[i]
 		if a.variable {
+			if !a.matches(b) {
+				return nil, false
+			}
 			params[a.value] = b
 		} else {
 			if (ignoreCase && strings.ToLower(a.value) != strings.ToLower(b)) ||
@@ -125,3 +204,64 @@ func splitUrl(route string) []string {
 
 	return strings.Split(route, "/")
 }
+
+//Build fills the route params of the path with the given values and returns the escaped URL
+func (p Path) Build(params MapString) (string, error) {
+	path := ""
+	for _, part := range p.parts {
+		if !part.variable {
+			path += "/" + url.PathEscape(part.value)
+			continue
+		}
+		value, ok := params[part.value]
+		if !ok {
+			return "", errors.New("missing route param \"" + part.value + "\" for " + p.ToString())
+		}
+		if !part.matches(value) {
+			return "", errors.New("route param \"" + part.value + "\" with value \"" + value + "\" does not satisfy the constraint " + part.constraint)
+		}
+		if part.wildcard {
+			for _, segment := range splitUrl(value) {
+				path += "/" + url.PathEscape(segment)
+			}
+			continue
+		}
+		if value == "" {
+			return "", errors.New("route param \"" + part.value + "\" must not be empty")
+		}
+		path += "/" + url.PathEscape(value)
+	}
+	if path == "" {
+		path = "/"
+	}
+	return path, nil
+}
+
+//params reads the route params out of segments, which are already known to match the path
+func (p Path) params(segments []string) map[string]string {
+	params := map[string]string{}
+	for i, part := range p.parts {
+		if part.wildcard {
+			params[part.value] = strings.Join(segments[i:], "/")
+		} else if part.variable {
+			params[part.value] = segments[i]
+		}
+	}
+	return params
+}
+
+//paramNames returns the names of the route params in the order they appear in
+func (p Path) paramNames() []string {
+	names := make([]string, 0)
+	for _, part := range p.parts {
+		if part.variable {
+			names = append(names, part.value)
+		}
+	}
+	return names
+}
+
+//hasWildcard checks if the last part captures the remainder of the path
+func (p Path) hasWildcard() bool {
+	return len(p.parts) != 0 && p.parts[len(p.parts)-1].wildcard
+}
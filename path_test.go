@@ -69,6 +69,20 @@ func TestConstructPath(t *testing.T) {
 				ignoreCase: true,
 			},
 		},
+		{
+			name: "/static/*filepath",
+			args: args{
+				pathString: "/static/*filepath",
+				ignoreCase: false,
+			},
+			want: Path{
+				parts: []pathPart{
+					{value: "static", variable: false},
+					{value: "filepath", variable: true, wildcard: true},
+				},
+				ignoreCase: false,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -89,6 +103,29 @@ func TestConstructPathPanic(t *testing.T) {
 	t.Errorf("did not panic")
 }
 
+func TestConstructPathWildcardPanic(t *testing.T) {
+	tests := []string{
+		"/static/*filepath/details",
+		"/static/:filepath/*filepath",
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			defer func() { recover() }()
+			ConstructPath(tt, false)
+			t.Errorf("did not panic")
+		})
+	}
+}
+
+func TestConstructPathConstraintPanic(t *testing.T) {
+	defer func() { recover() }()
+
+	//should panic because the regular expression is invalid
+	ConstructPath("/files/:name<[a-z>", false)
+
+	t.Errorf("did not panic")
+}
+
 func TestPath_Equals(t *testing.T) {
 	type args struct {
 		toCompare Path
@@ -143,6 +180,36 @@ func TestPath_Equals(t *testing.T) {
 			args: args{ConstructPath("/home/about", false)},
 			want: false,
 		},
+		{
+			name: "/static/*path == /static/*file",
+			path: ConstructPath("/static/*path", false),
+			args: args{ConstructPath("/static/*file", false)},
+			want: true,
+		},
+		{
+			name: "/static/*path != /static/:file",
+			path: ConstructPath("/static/*path", false),
+			args: args{ConstructPath("/static/:file", false)},
+			want: false,
+		},
+		{
+			name: "/users/:id<int> != /users/:id",
+			path: ConstructPath("/users/:id<int>", false),
+			args: args{ConstructPath("/users/:id", false)},
+			want: false,
+		},
+		{
+			name: "/users/:id<int> == /users/:uid<int>",
+			path: ConstructPath("/users/:id<int>", false),
+			args: args{ConstructPath("/users/:uid<int>", false)},
+			want: true,
+		},
+		{
+			name: "/static/*path != /static/index",
+			path: ConstructPath("/static/*path", false),
+			args: args{ConstructPath("/static/index", false)},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -231,6 +298,63 @@ func TestPath_Parse(t *testing.T) {
 			want:  nil,
 			want1: false,
 		},
+		{
+			name: "/static/*filepath",
+			path: ConstructPath("/static/*filepath", false),
+			args: args{route: "/static/css/main.css"},
+			want: map[string]string{
+				"filepath": "css/main.css",
+			},
+			want1: true,
+		},
+		{
+			name: "/static/*filepath",
+			path: ConstructPath("/static/*filepath", false),
+			args: args{route: "/static"},
+			want: map[string]string{
+				"filepath": "",
+			},
+			want1: true,
+		},
+		{
+			name: "/users/:id<int>",
+			path: ConstructPath("/users/:id<int>", false),
+			args: args{route: "/users/-42"},
+			want: map[string]string{
+				"id": "-42",
+			},
+			want1: true,
+		},
+		{
+			name:  "/users/:id<int>",
+			path:  ConstructPath("/users/:id<int>", false),
+			args:  args{route: "/users/me"},
+			want:  nil,
+			want1: false,
+		},
+		{
+			name: "/files/:name<[a-z0-9-]+>",
+			path: ConstructPath("/files/:name<[a-z0-9-]+>", false),
+			args: args{route: "/files/my-file-2"},
+			want: map[string]string{
+				"name": "my-file-2",
+			},
+			want1: true,
+		},
+		{
+			name:  "/files/:name<[a-z0-9-]+>",
+			path:  ConstructPath("/files/:name<[a-z0-9-]+>", false),
+			args:  args{route: "/files/my_file"},
+			want:  nil,
+			want1: false,
+		},
+		{
+			name:  "/static/*filepath",
+			path:  ConstructPath("/static/*filepath", false),
+			args:  args{route: "/"},
+			want:  nil,
+			want1: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -283,6 +407,9 @@ func TestPath_ToString(t *testing.T) {
 	add("/user/:id/", "/user/:id")
 	add("user/:id/", "/user/:id")
 	add("user/:id", "/user/:id")
+	add("/static/*filepath", "/static/*filepath")
+	add("/users/:id<int>", "/users/:id<int>")
+	add("/files/:name<[a-z0-9-]+>/", "/files/:name<[a-z0-9-]+>")
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,11 +1,17 @@
 package there
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"strings"
 )
 
 type HttpRequest struct {
@@ -16,20 +22,26 @@ type HttpRequest struct {
 	Body        *BodyReader
 	Params      *BasicReader
 	Headers     *BasicReader
+	Cookies     *BasicReader
 	RouteParams *RouteParamReader
 }
 
 func NewHttpRequest(responseWriter http.ResponseWriter, request *http.Request) HttpRequest {
 	paramReader := BasicReader(request.URL.Query())
 	headerReader := BasicReader(request.Header)
+	cookieReader := BasicReader{}
+	for _, cookie := range request.Cookies() {
+		cookieReader[cookie.Name] = append(cookieReader[cookie.Name], cookie.Value)
+	}
 	routeParamReader := RouteParamReader(MapString{})
 	return HttpRequest{
 		Request:        request,
 		ResponseWriter: responseWriter,
 		Method:         request.Method,
-		Body:           &BodyReader{request: request},
+		Body:           &BodyReader{request: request, maxMultipartMemory: defaultMaxMultipartMemory, maxBodyBytes: defaultMaxBodyBytes},
 		Params:         &paramReader,
 		Headers:        &headerReader,
+		Cookies:        &cookieReader,
 		RouteParams:    &routeParamReader,
 	}
 }
@@ -38,6 +50,11 @@ func (r *HttpRequest) Context() context.Context {
 	return r.Request.Context()
 }
 
+//Configuration returns the RouterConfiguration of the router serving the request, e.g. to use its handlers in a middleware
+func (r *HttpRequest) Configuration() *RouterConfiguration {
+	return configuration(r.Request)
+}
+
 func (r *HttpRequest) WithContext(ctx context.Context) {
 	*r.Request = *r.Request.WithContext(ctx)
 }
@@ -45,25 +62,225 @@ func (r *HttpRequest) WithContext(ctx context.Context) {
 //BodyReader reads the body and unmarshal it to the specified destination
 type BodyReader struct {
 	request *http.Request
+	//maxMultipartMemory is the amount of bytes of a multipart form, which are kept in memory.
+	//The remaining files are stored in temporary files.
+	maxMultipartMemory    int64
+	disallowUnknownFields bool
+	//maxBodyBytes limits the size of a buffered body, zero or negative values disable the limit
+	maxBodyBytes int64
+
+	//buffer holds the complete body, after HttpRequest.BufferBody was called
+	buffer   []byte
+	buffered bool
 }
 
-func (read BodyReader) BindJson(dest any) error {
-	return read.bind(dest, json.Unmarshal)
+//BufferBody reads the complete body once and keeps it in memory, so it can be read any number of times,
+//e.g. by a middleware verifying a signature and the endpoint binding it afterwards.
+//The size is limited by the MaxBodyBytes of the route or RouterConfiguration, ErrBodyTooLarge is returned if the body exceeds it.
+//Request.Body is replaced with a reader starting at the beginning of the buffer on every read of the BodyReader.
+func (r *HttpRequest) BufferBody() error {
+	read := r.Body
+	if read.buffered {
+		return nil
+	}
+	var body io.Reader = read.request.Body
+	if read.maxBodyBytes > 0 {
+		if read.request.ContentLength > read.maxBodyBytes {
+			return ErrBodyTooLarge
+		}
+		body = &limitedBody{ReadCloser: read.request.Body, remaining: read.maxBodyBytes}
+	}
+	data, err := ioutil.ReadAll(body)
+	read.request.Body.Close()
+	if err != nil {
+		return err
+	}
+	read.buffer = data
+	read.buffered = true
+	read.reader()
+	return nil
 }
 
-func (read BodyReader) BindXml(dest any) error {
-	return read.bind(dest, xml.Unmarshal)
+//reader returns the body of the request. A buffered body is rewound to the beginning.
+func (read BodyReader) reader() io.ReadCloser {
+	if read.buffered {
+		read.request.Body = ioutil.NopCloser(bytes.NewReader(read.buffer))
+	}
+	return read.request.Body
+}
+
+const defaultMaxMultipartMemory = 32 << 20
+
+//Form parses url encoded and multipart bodies and returns the form values
+func (read BodyReader) Form() (BasicReader, error) {
+	if err := read.parseForm(); err != nil {
+		return nil, err
+	}
+	return BasicReader(read.request.PostForm), nil
+}
+
+//File returns the first uploaded file of a multipart form with the given field name.
+//Returns http.ErrMissingFile, if there is no such file.
+func (read BodyReader) File(name string) (*FormFile, error) {
+	files, err := read.Files(name)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+//Files returns every uploaded file of a multipart form with the given field name.
+//Returns http.ErrMissingFile, if there is no such file.
+func (read BodyReader) Files(name string) ([]*FormFile, error) {
+	if err := read.parseForm(); err != nil {
+		return nil, err
+	}
+	form := read.request.MultipartForm
+	if form == nil || len(form.File[name]) == 0 {
+		return nil, http.ErrMissingFile
+	}
+	files := make([]*FormFile, 0, len(form.File[name]))
+	for _, header := range form.File[name] {
+		file, err := header.Open()
+		if err != nil {
+			for _, opened := range files {
+				opened.Close()
+			}
+			return nil, err
+		}
+		files = append(files, &FormFile{
+			File:        file,
+			Filename:    header.Filename,
+			Size:        header.Size,
+			ContentType: header.Header.Get(RequestHeaderContentType),
+			header:      header,
+		})
+	}
+	return files, nil
+}
+
+func (read BodyReader) parseForm() error {
+	if read.request.PostForm != nil {
+		return nil
+	}
+	read.reader()
+	if strings.HasPrefix(read.request.Header.Get(RequestHeaderContentType), ContentTypeMultipartFormDashData) {
+		return read.request.ParseMultipartForm(read.maxMultipartMemory)
+	}
+	return read.request.ParseForm()
+}
+
+//ErrBodyTooLarge is returned when reading more than the allowed bytes of a body,
+//see RouterConfiguration.MaxBodyBytes and RouteRouteGroupBuilder.MaxBodyBytes
+var ErrBodyTooLarge = errors.New("request body too large")
+
+//limitedBody fails with ErrBodyTooLarge, as soon as more than limit bytes are read
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		l.exceeded = true
+		return 0, ErrBodyTooLarge
+	}
+	// read one byte more than allowed to detect bodies exceeding the limit
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return n + int(l.remaining), ErrBodyTooLarge
+	}
+	return n, err
+}
+
+//FormFile is an uploaded file of a multipart form. Read it directly or use SaveTo.
+//Files exceeding RouterConfiguration.MaxMultipartMemory are stored in temporary files,
+//which are removed after the request is finished.
+type FormFile struct {
+	multipart.File
+	Filename    string
+	Size        int64
+	ContentType string
+
+	header *multipart.FileHeader
 }
 
-func (read BodyReader) bind(dest any, formatter func(data []byte, v any) error) error {
-	body, err := read.ToBytes()
+//SaveTo copies the complete file to the given path, no matter how much of it was read already
+func (f *FormFile) SaveTo(path string) error {
+	src, err := f.header.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
 	if err != nil {
 		return err
 	}
-	err = formatter(body, dest)
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
 	return err
 }
 
+//BindJson decodes the body to dest and checks its validate struct tags afterwards, see Validate.
+//Unknown fields are rejected, if RouterConfiguration.DisallowUnknownFields is set.
+func (read BodyReader) BindJson(dest any) error {
+	body := read.reader()
+	defer body.Close()
+	decoder := json.NewDecoder(body)
+	if read.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(dest); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return ErrTrailingData
+	}
+	return Validate(dest)
+}
+
+//BindXml decodes the body to dest and checks its validate struct tags afterwards, see Validate
+func (read BodyReader) BindXml(dest any) error {
+	body := read.reader()
+	defer body.Close()
+	decoder := xml.NewDecoder(body)
+	if err := decoder.Decode(dest); err != nil {
+		return err
+	}
+	//only whitespace and comments may follow the root element
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ErrTrailingData
+		}
+		switch t := token.(type) {
+		case xml.Comment:
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) != 0 {
+				return ErrTrailingData
+			}
+		default:
+			return ErrTrailingData
+		}
+	}
+	return Validate(dest)
+}
+
+//ErrTrailingData is returned by BindJson and BindXml, if the body contains more than a single value
+var ErrTrailingData = errors.New("request body contains data after the first value")
+
 func (read BodyReader) ToString() (string, error) {
 	data, err := read.ToBytes()
 	if err != nil {
@@ -73,8 +290,9 @@ func (read BodyReader) ToString() (string, error) {
 }
 
 func (read BodyReader) ToBytes() ([]byte, error) {
-	data, err := ioutil.ReadAll(read.request.Body)
-	defer read.request.Body.Close()
+	body := read.reader()
+	data, err := ioutil.ReadAll(body)
+	defer body.Close()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,204 @@
+package there
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ErrParamMissing is wrapped by the errors of the typed getters, if the key is not present
+var ErrParamMissing = errors.New("param is missing")
+
+var uuidMatcher = regexp.MustCompile("^(?:" + constraintTypes["uuid"] + ")$")
+
+type stringGetter interface {
+	Get(key string) (string, bool)
+}
+
+func getTyped[T any](reader stringGetter, key, typeName string, parse func(string) (T, error)) (T, error) {
+	value, ok := reader.Get(key)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %q", ErrParamMissing, key)
+	}
+	return parseTyped(key, value, typeName, parse)
+}
+
+func getTypedDefault[T any](reader stringGetter, key, typeName string, parse func(string) (T, error), defaultValue T) (T, error) {
+	value, ok := reader.Get(key)
+	if !ok {
+		return defaultValue, nil
+	}
+	return parseTyped(key, value, typeName, parse)
+}
+
+func parseTyped[T any](key, value, typeName string, parse func(string) (T, error)) (T, error) {
+	parsed, err := parse(value)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("param %q with value %q is not a valid %s: %w", key, value, typeName, err)
+	}
+	return parsed, nil
+}
+
+func parseInt64(value string) (int64, error) {
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func parseFloat(value string) (float64, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+func parseTime(layout string) func(string) (time.Time, error) {
+	return func(value string) (time.Time, error) {
+		return time.Parse(layout, value)
+	}
+}
+
+//parseUUID checks the format of the UUID and returns it in lowercase
+func parseUUID(value string) (string, error) {
+	if !uuidMatcher.MatchString(value) {
+		return "", errors.New("expected the format xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx")
+	}
+	return strings.ToLower(value), nil
+}
+
+//GetInt returns the value of the key as int. Returns an error wrapping ErrParamMissing, if the key is not present
+func (reader BasicReader) GetInt(key string) (int, error) {
+	return getTyped(reader, key, "int", strconv.Atoi)
+}
+
+//GetIntDefault returns the value of the key as int or defaultValue, if the key is not present
+func (reader BasicReader) GetIntDefault(key string, defaultValue int) (int, error) {
+	return getTypedDefault(reader, key, "int", strconv.Atoi, defaultValue)
+}
+
+func (reader BasicReader) GetInt64(key string) (int64, error) {
+	return getTyped(reader, key, "int64", parseInt64)
+}
+
+func (reader BasicReader) GetInt64Default(key string, defaultValue int64) (int64, error) {
+	return getTypedDefault(reader, key, "int64", parseInt64, defaultValue)
+}
+
+func (reader BasicReader) GetFloat(key string) (float64, error) {
+	return getTyped(reader, key, "float", parseFloat)
+}
+
+func (reader BasicReader) GetFloatDefault(key string, defaultValue float64) (float64, error) {
+	return getTypedDefault(reader, key, "float", parseFloat, defaultValue)
+}
+
+func (reader BasicReader) GetBool(key string) (bool, error) {
+	return getTyped(reader, key, "bool", strconv.ParseBool)
+}
+
+func (reader BasicReader) GetBoolDefault(key string, defaultValue bool) (bool, error) {
+	return getTypedDefault(reader, key, "bool", strconv.ParseBool, defaultValue)
+}
+
+func (reader BasicReader) GetDuration(key string) (time.Duration, error) {
+	return getTyped(reader, key, "duration", time.ParseDuration)
+}
+
+func (reader BasicReader) GetDurationDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	return getTypedDefault(reader, key, "duration", time.ParseDuration, defaultValue)
+}
+
+//GetTime parses the value of the key with the layout, e.g. time.RFC3339
+func (reader BasicReader) GetTime(key, layout string) (time.Time, error) {
+	return getTyped(reader, key, "time", parseTime(layout))
+}
+
+func (reader BasicReader) GetTimeDefault(key, layout string, defaultValue time.Time) (time.Time, error) {
+	return getTypedDefault(reader, key, "time", parseTime(layout), defaultValue)
+}
+
+//GetUUID returns the value of the key in lowercase, if it is a valid UUID
+func (reader BasicReader) GetUUID(key string) (string, error) {
+	return getTyped(reader, key, "uuid", parseUUID)
+}
+
+func (reader BasicReader) GetUUIDDefault(key string, defaultValue string) (string, error) {
+	return getTypedDefault(reader, key, "uuid", parseUUID, defaultValue)
+}
+
+//GetIntSlice converts every value of the key to int, e.g. ?id=1&id=2
+func (reader BasicReader) GetIntSlice(key string) ([]int, error) {
+	list, ok := reader.GetSlice(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrParamMissing, key)
+	}
+	ints := make([]int, len(list))
+	for i, value := range list {
+		parsed, err := parseTyped(key, value, "int", strconv.Atoi)
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = parsed
+	}
+	return ints, nil
+}
+
+//GetInt returns the value of the key as int. Returns an error wrapping ErrParamMissing, if the key is not present
+func (reader RouteParamReader) GetInt(key string) (int, error) {
+	return getTyped(reader, key, "int", strconv.Atoi)
+}
+
+//GetIntDefault returns the value of the key as int or defaultValue, if the key is not present
+func (reader RouteParamReader) GetIntDefault(key string, defaultValue int) (int, error) {
+	return getTypedDefault(reader, key, "int", strconv.Atoi, defaultValue)
+}
+
+func (reader RouteParamReader) GetInt64(key string) (int64, error) {
+	return getTyped(reader, key, "int64", parseInt64)
+}
+
+func (reader RouteParamReader) GetInt64Default(key string, defaultValue int64) (int64, error) {
+	return getTypedDefault(reader, key, "int64", parseInt64, defaultValue)
+}
+
+func (reader RouteParamReader) GetFloat(key string) (float64, error) {
+	return getTyped(reader, key, "float", parseFloat)
+}
+
+func (reader RouteParamReader) GetFloatDefault(key string, defaultValue float64) (float64, error) {
+	return getTypedDefault(reader, key, "float", parseFloat, defaultValue)
+}
+
+func (reader RouteParamReader) GetBool(key string) (bool, error) {
+	return getTyped(reader, key, "bool", strconv.ParseBool)
+}
+
+func (reader RouteParamReader) GetBoolDefault(key string, defaultValue bool) (bool, error) {
+	return getTypedDefault(reader, key, "bool", strconv.ParseBool, defaultValue)
+}
+
+func (reader RouteParamReader) GetDuration(key string) (time.Duration, error) {
+	return getTyped(reader, key, "duration", time.ParseDuration)
+}
+
+func (reader RouteParamReader) GetDurationDefault(key string, defaultValue time.Duration) (time.Duration, error) {
+	return getTypedDefault(reader, key, "duration", time.ParseDuration, defaultValue)
+}
+
+//GetTime parses the value of the key with the layout, e.g. time.RFC3339
+func (reader RouteParamReader) GetTime(key, layout string) (time.Time, error) {
+	return getTyped(reader, key, "time", parseTime(layout))
+}
+
+func (reader RouteParamReader) GetTimeDefault(key, layout string, defaultValue time.Time) (time.Time, error) {
+	return getTypedDefault(reader, key, "time", parseTime(layout), defaultValue)
+}
+
+//GetUUID returns the value of the key in lowercase, if it is a valid UUID
+func (reader RouteParamReader) GetUUID(key string) (string, error) {
+	return getTyped(reader, key, "uuid", parseUUID)
+}
+
+func (reader RouteParamReader) GetUUIDDefault(key string, defaultValue string) (string, error) {
+	return getTypedDefault(reader, key, "uuid", parseUUID, defaultValue)
+}
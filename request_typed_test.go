@@ -0,0 +1,70 @@
+package there
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	typedReader = BasicReader{
+		"int":      []string{"42", "7"},
+		"float":    []string{"1.5"},
+		"bool":     []string{"true"},
+		"duration": []string{"1m30s"},
+		"time":     []string{"2022-05-01"},
+		"uuid":     []string{"123E4567-E89B-12D3-A456-426614174000"},
+		"text":     []string{"abc"},
+	}
+	typedRouteParamReader = RouteParamReader{
+		"int":  "42",
+		"text": "abc",
+	}
+)
+
+func TestParamReader_Typed(t *testing.T) {
+	tests := []struct {
+		name    string
+		get     func() (any, error)
+		want    any
+		wantErr string
+	}{
+		{name: "GetInt", get: func() (any, error) { return typedReader.GetInt("int") }, want: 42},
+		{name: "GetInt64", get: func() (any, error) { return typedReader.GetInt64("int") }, want: int64(42)},
+		{name: "GetFloat", get: func() (any, error) { return typedReader.GetFloat("float") }, want: 1.5},
+		{name: "GetBool", get: func() (any, error) { return typedReader.GetBool("bool") }, want: true},
+		{name: "GetDuration", get: func() (any, error) { return typedReader.GetDuration("duration") }, want: 90 * time.Second},
+		{name: "GetTime", get: func() (any, error) { return typedReader.GetTime("time", "2006-01-02") }, want: time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "GetUUID", get: func() (any, error) { return typedReader.GetUUID("uuid") }, want: "123e4567-e89b-12d3-a456-426614174000"},
+		{name: "GetIntSlice", get: func() (any, error) { return typedReader.GetIntSlice("int") }, want: []int{42, 7}},
+		{name: "GetIntDefault missing", get: func() (any, error) { return typedReader.GetIntDefault("missing", 5) }, want: 5},
+		{name: "GetBoolDefault present", get: func() (any, error) { return typedReader.GetBoolDefault("bool", false) }, want: true},
+		{name: "GetInt missing", get: func() (any, error) { return typedReader.GetInt("missing") }, want: 0, wantErr: `"missing"`},
+		{name: "GetInt invalid", get: func() (any, error) { return typedReader.GetInt("text") }, want: 0, wantErr: `param "text" with value "abc" is not a valid int`},
+		{name: "GetIntDefault invalid", get: func() (any, error) { return typedReader.GetIntDefault("text", 5) }, want: 0, wantErr: `param "text"`},
+		{name: "GetUUID invalid", get: func() (any, error) { return typedReader.GetUUID("text") }, want: "", wantErr: "not a valid uuid"},
+		{name: "GetIntSlice invalid", get: func() (any, error) { return typedReader.GetIntSlice("uuid") }, want: []int(nil), wantErr: `param "uuid"`},
+		{name: "RouteParam GetInt", get: func() (any, error) { return typedRouteParamReader.GetInt("int") }, want: 42},
+		{name: "RouteParam GetInt64Default", get: func() (any, error) { return typedRouteParamReader.GetInt64Default("missing", 3) }, want: int64(3)},
+		{name: "RouteParam GetFloat invalid", get: func() (any, error) { return typedRouteParamReader.GetFloat("text") }, want: 0.0, wantErr: `param "text"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if (err != nil) != (tt.wantErr != "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamReader_TypedMissing(t *testing.T) {
+	if _, err := typedRouteParamReader.GetBool("missing"); !errors.Is(err, ErrParamMissing) {
+		t.Errorf("error = %v, want ErrParamMissing", err)
+	}
+}
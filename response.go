@@ -2,11 +2,9 @@ package there
 
 import (
 	"bytes"
-	"encoding/json"
-	"encoding/xml"
-	"fmt"
 	"html/template"
 	"net/http"
+	"path/filepath"
 )
 
 //HttpResponse is the base for every return you can make in an Endpoint.
@@ -85,16 +83,37 @@ func String(code int, data string) HttpResponse {
 	return Bytes(code, []byte(data))
 }
 
-//Error takes a StatusCode and err which rendering is specified by the Serializers in the RouterConfiguration
+//Error takes a StatusCode and err which rendering is specified by RouterConfiguration.ErrorRenderer.
+//By default, the error is serialized by the Serializers in the RouterConfiguration, see DefaultErrorRenderer.
 func Error(code int, err any) HttpResponse {
-	return Json(code, MapString{
-		"error": fmt.Sprint(err),
-	})
+	return &errorResponse{code: code, err: toError(err)}
+}
+
+type errorResponse struct {
+	code int
+	err  error
+}
+
+func (e errorResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	configuration(r).ErrorRenderer(r, e.code, e.err).ServeHTTP(rw, r)
+}
+
+//ValidationFailed renders the ValidationErrors with StatusUnprocessableEntity and the RouterConfiguration.ErrorRenderer.
+//The renderers of this package group the messages by the JSON path of the field:
+//
+//	{"error": "validation failed: ...", "fields": {"address.city": ["is required"]}}
+func ValidationFailed(errs ValidationErrors) HttpResponse {
+	return Error(StatusUnprocessableEntity, errs)
 }
 
 //Html takes a status code, the path to the html file and a map for the template parsing
 func Html(code int, file string, template any) HttpResponse {
-	content, err := parseTemplate(file, template)
+	return HtmlWithFuncs(code, file, nil, template)
+}
+
+//HtmlWithFuncs works like Html, but makes the funcs available in the template, e.g. Router.TemplateFuncs
+func HtmlWithFuncs(code int, file string, funcs template.FuncMap, data any) HttpResponse {
+	content, err := parseTemplate(file, funcs, data)
 	if err != nil {
 		panic(err)
 	}
@@ -103,8 +122,8 @@ func Html(code int, file string, template any) HttpResponse {
 	}, Bytes(code, []byte(*content)))
 }
 
-func parseTemplate(templateFileName string, data any) (*string, error) {
-	t, err := template.ParseFiles(templateFileName)
+func parseTemplate(templateFileName string, funcs template.FuncMap, data any) (*string, error) {
+	t, err := template.New(filepath.Base(templateFileName)).Funcs(funcs).ParseFiles(templateFileName)
 	if err != nil {
 		return nil, err
 	}
@@ -116,15 +135,9 @@ func parseTemplate(templateFileName string, data any) (*string, error) {
 	return &body, nil
 }
 
-//Json takes a StatusCode and data which gets marshaled to Json
+//Json takes a StatusCode and data which gets marshaled by the Serializer for ContentTypeApplicationJson
 func Json(code int, data any) HttpResponse {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		panic(err)
-	}
-	return WithHeaders(MapString{
-		ResponseHeaderContentType: ContentTypeApplicationJson,
-	}, Bytes(code, jsonData))
+	return Serialize(code, ContentTypeApplicationJson, data)
 }
 
 //Message takes StatusCode and a message which will be put into a JSON object
@@ -148,13 +161,7 @@ func (j redirectResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	http.Redirect(rw, r, j.url, j.code)
 }
 
-//Xml takes a StatusCode and data which gets marshaled to Xml
+//Xml takes a StatusCode and data which gets marshaled by the Serializer for ContentTypeApplicationXml
 func Xml(code int, data any) HttpResponse {
-	xmlData, err := xml.Marshal(data)
-	if err != nil {
-		panic(err)
-	}
-	return WithHeaders(MapString{
-		ResponseHeaderContentType: ContentTypeApplicationXml,
-	}, Bytes(code, xmlData))
+	return Serialize(code, ContentTypeApplicationXml, data)
 }
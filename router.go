@@ -1,9 +1,15 @@
 package there
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"html/template"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 type Router struct {
@@ -16,20 +22,55 @@ type Router struct {
 	//routes is a list of Routes which checks for duplicate entries
 	//on insert.
 	routes RouteManager
+	//tree holds the same Routes as routes and is used to match requests
+	tree *routeTree
+	//namedRoutes are the Routes which have been given a name
+	namedRoutes map[string]*Route
+
+	startHooks    []func() error
+	shutdownHooks []func(ctx context.Context) error
+
+	//closing is cancelled when the server shuts down, to end long-lived responses like EventStream,
+	//because http.Server.Shutdown does not cancel the context of in-flight requests
+	closing       context.Context
+	cancelClosing context.CancelFunc
 }
 
 func NewRouter() *Router {
 	r := &Router{
 		globalMiddlewares: make([]Middleware, 0),
 		routes:            make([]*Route, 0),
+		tree:              newRouteTree(),
+		namedRoutes:       map[string]*Route{},
 		Server:            &http.Server{},
 		Configuration: &RouterConfiguration{
+			HandleHead:         true,
+			HandleOptions:      true,
+			ShutdownTimeout:    10 * time.Second,
+			MaxMultipartMemory: defaultMaxMultipartMemory,
+			MaxBodyBytes:       defaultMaxBodyBytes,
+			Serializers:        defaultSerializers(),
+			DefaultMediaType:   ContentTypeApplicationJson,
+			ErrorRenderer:      DefaultErrorRenderer,
+			ErrorMapper:        DefaultErrorMapper,
+
+			EventStreamKeepAlive: 15 * time.Second,
+			WebSocketCheckOrigin: SameOrigin,
+
 			RouteNotFoundHandler: func(request HttpRequest) HttpResponse {
 				return Error(StatusNotFound, errors.New("could not find route "+request.Method+" "+request.Request.URL.Path))
 			},
+			MethodNotAllowedHandler: func(request HttpRequest) HttpResponse {
+				return Error(StatusMethodNotAllowed, errors.New("method "+request.Method+" is not allowed for route "+request.Request.URL.Path))
+			},
+			BodyTooLargeHandler: func(request HttpRequest) HttpResponse {
+				return Error(StatusRequestEntityTooLarge, ErrBodyTooLarge)
+			},
 		},
 	}
 	r.Server.Handler = r
+	r.closing, r.cancelClosing = context.WithCancel(context.Background())
+	r.Server.RegisterOnShutdown(r.cancelClosing)
 	r.RouteGroup = NewRouteGroup(r, "/")
 	return r
 }
@@ -42,22 +83,164 @@ func (p Port) ToAddr() string {
 
 func (router *Router) Listen(port Port) error {
 	router.Server.Addr = port.ToAddr()
+	if err := router.start(); err != nil {
+		return err
+	}
 	return router.Server.ListenAndServe()
 }
 
 func (router *Router) ListenToTLS(port Port, certFile, keyFile string) error {
 	router.Server.Addr = port.ToAddr()
+	if err := router.start(); err != nil {
+		return err
+	}
 	return router.Server.ListenAndServeTLS(certFile, keyFile)
 }
 
+//ListenWithSignals works like Listen, but shuts the router down gracefully on SIGINT or SIGTERM.
+//In-flight requests get RouterConfiguration.ShutdownTimeout to finish. Returns nil after a clean shutdown.
+func (router *Router) ListenWithSignals(port Port) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- router.Listen(port)
+	}()
+
+	select {
+	case err := <-listenErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), router.Configuration.ShutdownTimeout)
+	defer cancel()
+	err := router.Shutdown(shutdownCtx)
+	if lErr := <-listenErr; err == nil && !errors.Is(lErr, http.ErrServerClosed) {
+		err = lErr
+	}
+	return err
+}
+
+//Shutdown stops accepting new connections, ends open EventStream responses, waits for in-flight requests to finish
+//and then runs the OnShutdown hooks in the order they were registered.
+//The hooks run even if draining fails, the first error is returned.
+func (router *Router) Shutdown(ctx context.Context) error {
+	router.cancelClosing()
+	err := router.Server.Shutdown(ctx)
+	for _, hook := range router.shutdownHooks {
+		if hookErr := hook(ctx); hookErr != nil && err == nil {
+			err = hookErr
+		}
+	}
+	return err
+}
+
+//OnStart registers a hook, which runs before the router starts listening.
+//If a hook returns an error, the router does not start and the error is returned by Listen.
+func (router *Router) OnStart(hook func() error) *Router {
+	router.startHooks = append(router.startHooks, hook)
+	return router
+}
+
+//OnShutdown registers a hook, which runs in Shutdown after in-flight requests were drained, e.g. to close a database pool
+func (router *Router) OnShutdown(hook func(ctx context.Context) error) *Router {
+	router.shutdownHooks = append(router.shutdownHooks, hook)
+	return router
+}
+
+func (router *Router) start() error {
+	for _, hook := range router.startHooks {
+		if err := hook(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 //Use registers a Middleware
 func (router *Router) Use(middleware Middleware) *Router {
 	router.globalMiddlewares = append(router.globalMiddlewares, middleware)
 	return router
 }
 
+//URL builds the path of the route with the given name and fills in its route params
+func (router *Router) URL(name string, params MapString) (string, error) {
+	route, ok := router.namedRoutes[name]
+	if !ok {
+		return "", errors.New("there is no route with the name \"" + name + "\"")
+	}
+	return route.Path.Build(params)
+}
+
+//TemplateFuncs returns the functions for html templates, which depend on the router.
+//
+//	url "user.show" "id" "42"
+//
+//builds the URL of a named route with the route params given as key value pairs.
+func (router *Router) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"url": func(name string, pairs ...string) (string, error) {
+			if len(pairs)%2 != 0 {
+				return "", errors.New("url for \"" + name + "\" needs the route params as key value pairs")
+			}
+			params := MapString{}
+			for i := 0; i < len(pairs); i += 2 {
+				params[pairs[i]] = pairs[i+1]
+			}
+			return router.URL(name, params)
+		},
+	}
+}
+
 //RouterConfiguration is a straightforward place to override default behavior of the router
 type RouterConfiguration struct {
 	//RouteNotFoundHandler gets invoked, when the specified URL and method have no handlers
 	RouteNotFoundHandler Endpoint
+	//MethodNotAllowedHandler gets invoked, when the specified URL has handlers, but none for the method.
+	//The Allow header is already populated with the methods of those handlers.
+	MethodNotAllowedHandler Endpoint
+	//HandleHead answers HEAD requests with the GET route of the URL, if there is no HEAD route.
+	//The endpoint gets executed, but the body is discarded.
+	HandleHead bool
+	//HandleOptions answers OPTIONS requests with the methods of the URL in the Allow header, if there is no OPTIONS route
+	HandleOptions bool
+	//ShutdownTimeout is the time in-flight requests get to finish, when ListenWithSignals receives a signal
+	ShutdownTimeout time.Duration
+	//MaxMultipartMemory is the amount of bytes of a multipart form kept in memory, the remaining files spill to temporary files
+	MaxMultipartMemory int64
+	//MaxBodyBytes is the maximum size of a request body, which can be overridden per route with RouteRouteGroupBuilder.MaxBodyBytes.
+	//A value of zero or less disables the limit.
+	MaxBodyBytes int64
+	//BodyTooLargeHandler gets invoked, when the Content-Length exceeds the limit or the endpoint read past it before returning its response.
+	//In the latter case, the response of the endpoint is discarded. Responses which read the body while they are served,
+	//like mounted handlers or HttpResponseFunc, only get ErrBodyTooLarge from Read and answer the request themselves.
+	BodyTooLargeHandler Endpoint
+	//DisallowUnknownFields makes BodyReader.BindJson fail on fields, which do not exist in the destination
+	DisallowUnknownFields bool
+	//Serializers marshal the data of Json, Xml, Error, Serialize and Negotiate responses by media type.
+	//JSON, XML, YAML and msgpack are registered by default, other formats can be added or the defaults replaced.
+	Serializers Serializers
+	//DefaultMediaType is used by Negotiate, if the request has no Accept header or it accepts any media type
+	DefaultMediaType string
+	//ErrorRenderer renders every Error response, including the ones of the handlers above and panics caught by middlewares.Recoverer.
+	//Use ProblemDetailsRenderer for RFC 7807 responses or NegotiatingErrorRenderer to respect the Accept header.
+	ErrorRenderer ErrorRenderer
+	//ErrorMapper chooses the StatusCode for the errors returned by an ErrorEndpoint
+	ErrorMapper ErrorMapper
+	//EventStreamKeepAlive is the interval of the comments EventStream responses send to keep idle connections open.
+	//A value of zero or less disables them.
+	EventStreamKeepAlive time.Duration
+	//TemplateEngine renders the HtmlTemplate responses, see Router.Templates
+	TemplateEngine *TemplateEngine
+	//CookieKeys sign and encrypt the cookies of WithSignedCookies and WithEncryptedCookies. New cookies use the first key,
+	//the others are only used to read cookies. Add a new key in front to rotate them. Each key should have at least 32 random bytes.
+	CookieKeys [][]byte
+	//WebSocketCheckOrigin decides if a WebSocket upgrade with the Origin of the request is allowed, SameOrigin by default.
+	//Set it to nil to allow every origin.
+	WebSocketCheckOrigin func(request *http.Request) bool
 }
+
+const defaultMaxBodyBytes = 10 << 20
@@ -22,3 +22,51 @@ func TestPort_ToAddr(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter_URL(t *testing.T) {
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+	router := NewRouter()
+	router.Get("/", handler).Name("home")
+	router.Group("/users").Get("/:id<int>", handler).Name("user.show")
+	router.Get("/files/:name/*path", handler).Name("file")
+
+	tests := []struct {
+		name    string
+		route   string
+		params  MapString
+		want    string
+		wantErr bool
+	}{
+		{name: "root", route: "home", want: "/"},
+		{name: "param", route: "user.show", params: MapString{"id": "42"}, want: "/users/42"},
+		{name: "wildcard", route: "file", params: MapString{"name": "a b", "path": "css/main.css"}, want: "/files/a%20b/css/main.css"},
+		{name: "missing param", route: "user.show", params: MapString{}, wantErr: true},
+		{name: "constraint", route: "user.show", params: MapString{"id": "me"}, wantErr: true},
+		{name: "unknown name", route: "user.delete", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := router.URL(tt.route, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("URL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("URL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteRouteGroupBuilder_NamePanic(t *testing.T) {
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+	router := NewRouter()
+	router.Get("/a", handler).Name("route")
+
+	defer func() { recover() }()
+	router.Get("/b", handler).Name("route")
+	t.Errorf("did not panic")
+}
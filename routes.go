@@ -2,15 +2,21 @@ package there
 
 import (
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 )
 
 type RouteGroup struct {
 	*Router
 	prefix string
+
+	//parent is the group this group was created from and nil for groups created with NewRouteGroup
+	parent      *RouteGroup
+	middlewares []Middleware
 }
 
-func (group RouteGroup) Group(prefix string) *RouteGroup {
+func (group *RouteGroup) Group(prefix string) *RouteGroup {
 
 	prefix = strings.TrimPrefix(prefix, "/")
 
@@ -23,7 +29,24 @@ func (group RouteGroup) Group(prefix string) *RouteGroup {
 	return &RouteGroup{
 		Router: group.Router,
 		prefix: group.prefix + prefix,
+		parent: group,
+	}
+}
+
+//UseGroup registers a Middleware for every route of the group and its nested groups, no matter if they
+//were added before or after. Global middlewares run first, followed by the middlewares of the outer groups,
+//the ones of this group and lastly the middlewares of the route. Router.Use stays global on every group.
+func (group *RouteGroup) UseGroup(middleware Middleware) *RouteGroup {
+	group.middlewares = append(group.middlewares, middleware)
+	return group
+}
+
+//allMiddlewares returns the middlewares of the group and its parents, starting with the outermost group
+func (group *RouteGroup) allMiddlewares() []Middleware {
+	if group == nil {
+		return nil
 	}
+	return append(group.parent.allMiddlewares(), group.middlewares...)
 }
 
 func NewRouteGroup(router *Router, route string) *RouteGroup {
@@ -45,12 +68,37 @@ func NewRouteGroup(router *Router, route string) *RouteGroup {
 
 type Endpoint func(request HttpRequest) HttpResponse
 
+//ErrorEndpoint is an Endpoint, which returns an error instead of panicking.
+//The error is rendered with the StatusCode RouterConfiguration.ErrorMapper returns for it.
+//ValidationErrors are rendered with ValidationFailed. Register it with ErrorEndpoint.Endpoint:
+//
+//	router.Get("/user/:id", ErrorEndpoint(GetUser).Endpoint())
+type ErrorEndpoint func(request HttpRequest) (HttpResponse, error)
+
+//Endpoint converts the ErrorEndpoint to an Endpoint, which renders the returned error
+func (e ErrorEndpoint) Endpoint() Endpoint {
+	return func(request HttpRequest) HttpResponse {
+		response, err := e(request)
+		if err != nil {
+			return errorEndpointResponse(err)
+		}
+		return response
+	}
+}
+
 //Route adds attributes to an Endpoint func
 type Route struct {
 	Endpoint    Endpoint
 	Methods     []string
 	Path        Path
 	Middlewares []Middleware
+	//Name identifies the Route for Router.URL and is empty, if the Route has no name
+	Name string
+
+	//group is the RouteGroup the Route was registered with
+	group *RouteGroup
+	//maxBodyBytes overrides RouterConfiguration.MaxBodyBytes if not zero, negative values disable the limit
+	maxBodyBytes int64
 }
 
 //OverlapsWith checks if an Route somehow overlaps with another container. For this to be true, the path and at least one method must equal
@@ -82,12 +130,14 @@ func (group *RouteGroup) Handle(path string, endpoint Endpoint, methods ...strin
 	}
 
 	route := &Route{
-		endpoint,
-		methods,
-		ConstructPath(path, false),
-		make([]Middleware, 0),
+		Endpoint:    endpoint,
+		Methods:     methods,
+		Path:        ConstructPath(path, false),
+		Middlewares: make([]Middleware, 0),
+		group:       group,
 	}
 	group.routes.AddRoute(route)
+	group.tree.Insert(route)
 
 	return &RouteRouteGroupBuilder{
 		route,
@@ -95,6 +145,45 @@ func (group *RouteGroup) Handle(path string, endpoint Endpoint, methods ...strin
 	}
 }
 
+//mountPathParam is the name of the wildcard route param, which captures the path below a mounted handler
+const mountPathParam = "mountPath"
+
+//Mount forwards every method and sub-path under prefix to the handler. The prefix is stripped from the
+//URL path, so a mounted *Router or http.FileServer sees the path relative to the prefix.
+//Reading past the body limit fails with ErrBodyTooLarge, but the handler is responsible for the response.
+func (group *RouteGroup) Mount(prefix string, handler http.Handler) *RouteRouteGroupBuilder {
+	return group.mount(prefix, handler, true)
+}
+
+//MountKeepPrefix works like Mount, but forwards the request with the complete URL path.
+//Useful for handlers like net/http/pprof, which expect to be served under a fixed path.
+func (group *RouteGroup) MountKeepPrefix(prefix string, handler http.Handler) *RouteRouteGroupBuilder {
+	return group.mount(prefix, handler, false)
+}
+
+func (group *RouteGroup) mount(prefix string, handler http.Handler, stripPrefix bool) *RouteRouteGroupBuilder {
+	prefix = strings.TrimSuffix(prefix, "/")
+	endpoint := func(request HttpRequest) HttpResponse {
+		if !stripPrefix {
+			return handler
+		}
+		path := "/" + request.RouteParams.GetDefault(mountPathParam, "")
+		if path != "/" && strings.HasSuffix(request.Request.URL.Path, "/") {
+			path += "/"
+		}
+		return HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = path
+			r2.URL.RawPath = ""
+			handler.ServeHTTP(rw, r2)
+		})
+	}
+	return group.Handle(prefix+"/*"+mountPathParam, endpoint, AllMethods...)
+}
+
 type RouteRouteGroupBuilder struct {
 	*Route
 	*RouteGroup
@@ -142,6 +231,29 @@ func (group *RouteRouteGroupBuilder) With(middleware Middleware) *RouteRouteGrou
 	return group
 }
 
+//MaxBodyBytes overrides RouterConfiguration.MaxBodyBytes for the route, a negative value disables the limit
+func (group *RouteRouteGroupBuilder) MaxBodyBytes(limit int64) *RouteRouteGroupBuilder {
+	group.Route.maxBodyBytes = limit
+	if limit == 0 {
+		group.Route.maxBodyBytes = -1
+	}
+	return group
+}
+
+//Name registers the route under the given name, so its URL can be built with Router.URL
+func (group *RouteRouteGroupBuilder) Name(name string) *RouteRouteGroupBuilder {
+	Assert(name != "", "route name must not be empty")
+	if existing, ok := group.Router.namedRoutes[name]; ok && existing != group.Route {
+		panic("The route name \"" + name + "\" is already used by \"" + existing.ToString() + "\"")
+	}
+	if group.Route.Name != "" {
+		delete(group.Router.namedRoutes, group.Route.Name)
+	}
+	group.Route.Name = name
+	group.Router.namedRoutes[name] = group.Route
+	return group
+}
+
 func (group *RouteRouteGroupBuilder) IgnoreCase() *RouteRouteGroupBuilder {
 	// cancel if already ignore case
 	if group.Route.Path.ignoreCase {
@@ -150,9 +262,11 @@ func (group *RouteRouteGroupBuilder) IgnoreCase() *RouteRouteGroupBuilder {
 
 	// delete route
 	group.routes.RemoveRoute(group.Route)
+	group.tree.Remove(group.Route)
 
 	group.Route.Path.ignoreCase = true
 	group.routes.AddRoute(group.Route)
+	group.tree.Insert(group.Route)
 
 	return group
 }
@@ -182,8 +296,9 @@ func (r *RouteManager) AddRoute(routeToAdd *Route) *Route {
 func (r *RouteManager) RemoveRoute(toRemove *Route) {
 
 	for i, container := range *r {
-		if container.Path.Equals(toRemove.Path) {
+		if container == toRemove {
 			*r = append((*r)[:i], (*r)[i+1:]...)
+			return
 		}
 	}
 
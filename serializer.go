@@ -0,0 +1,266 @@
+package there
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//Serializer marshals data to the media type it is registered for in RouterConfiguration.Serializers
+type Serializer func(data any) ([]byte, error)
+
+//Serializers maps media types to their Serializer. JSON, XML, YAML and msgpack are registered by default,
+//other formats are registered the same way:
+//
+//	router.Configuration.Serializers["application/toml"] = toml.Marshal
+type Serializers map[string]Serializer
+
+func defaultSerializers() Serializers {
+	return Serializers{
+		ContentTypeApplicationJson:         json.Marshal,
+		ContentTypeApplicationXml:          xml.Marshal,
+		ContentTypeTextXml:                 xml.Marshal,
+		ContentTypeApplicationYaml:         MarshalYaml,
+		ContentTypeApplicationXDashMsgpack: MarshalMsgpack,
+	}
+}
+
+//ErrNotAcceptable is rendered by Negotiate, if no Serializer matches the Accept header
+var ErrNotAcceptable = errors.New("none of the accepted media types can be served")
+
+type routerContextKey struct{}
+
+//configuration returns the RouterConfiguration of the router serving the request.
+//Outside a router, the defaults of NewRouter are used.
+func configuration(r *http.Request) *RouterConfiguration {
+	if router, ok := r.Context().Value(routerContextKey{}).(*Router); ok {
+		return router.Configuration
+	}
+	defaultConfigurationOnce.Do(func() {
+		defaultConfiguration = NewRouter().Configuration
+	})
+	return defaultConfiguration
+}
+
+var (
+	defaultConfiguration     *RouterConfiguration
+	defaultConfigurationOnce sync.Once
+)
+
+//Serialize takes a StatusCode, the media type and data which gets marshaled by the Serializer registered for the media type
+func Serialize(code int, mediaType string, data any) HttpResponse {
+	return &serializedResponse{code: code, mediaType: mediaType, data: data}
+}
+
+type serializedResponse struct {
+	code      int
+	mediaType string
+	data      any
+}
+
+func (s serializedResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	serializer, ok := configuration(r).Serializers[s.mediaType]
+	if !ok {
+		panic(errors.New("there is no serializer for the media type " + s.mediaType))
+	}
+	data, err := serializer(s.data)
+	if err != nil {
+		panic(err)
+	}
+	WithHeaders(MapString{
+		ResponseHeaderContentType: s.mediaType,
+	}, Bytes(s.code, data)).ServeHTTP(rw, r)
+}
+
+//Negotiate takes a StatusCode and data which gets marshaled to the media type the client prefers.
+//The Accept header is matched against RouterConfiguration.Serializers, respecting q-values and wildcards.
+//Without an Accept header, RouterConfiguration.DefaultMediaType is used. If nothing matches, StatusNotAcceptable is rendered.
+func Negotiate(code int, data any) HttpResponse {
+	return &negotiatedResponse{code: code, data: data}
+}
+
+type negotiatedResponse struct {
+	code int
+	data any
+}
+
+func (n negotiatedResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	mediaType, ok := negotiateMediaType(r.Header.Get(RequestHeaderAccept), configuration(r))
+	if !ok {
+		Error(StatusNotAcceptable, ErrNotAcceptable).ServeHTTP(rw, r)
+		return
+	}
+	rw.Header().Add(ResponseHeaderVary, RequestHeaderAccept)
+	Serialize(n.code, mediaType, n.data).ServeHTTP(rw, r)
+}
+
+//acceptedRange is a single media range of an Accept header
+type acceptedRange struct {
+	mediaType string
+	quality   float64
+}
+
+//specificity ranks exact media types before type/* before */*
+func (a acceptedRange) specificity() int {
+	switch {
+	case a.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(a.mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (a acceptedRange) matches(mediaType string) bool {
+	switch a.specificity() {
+	case 0:
+		return true
+	case 1:
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(a.mediaType, "*"))
+	default:
+		return a.mediaType == mediaType
+	}
+}
+
+//parseAccept returns the media ranges of the header ordered by quality and specificity
+func parseAccept(header string) []acceptedRange {
+	var ranges []acceptedRange
+	for _, entry := range strings.Split(header, ",") {
+		params := strings.Split(entry, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		accepted := acceptedRange{mediaType: mediaType, quality: 1}
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				accepted.quality = q
+			}
+		}
+		ranges = append(ranges, accepted)
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].quality != ranges[j].quality {
+			return ranges[i].quality > ranges[j].quality
+		}
+		return ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
+
+//negotiateMediaType returns the registered media type, which fits the Accept header best.
+//Wildcards prefer the DefaultMediaType and fall back to the alphabetically first match.
+func negotiateMediaType(header string, config *RouterConfiguration) (string, bool) {
+	if strings.TrimSpace(header) == "" {
+		_, ok := config.Serializers[config.DefaultMediaType]
+		return config.DefaultMediaType, ok
+	}
+	ranges := parseAccept(header)
+	//rejected reports, if the most specific range matching the media type has a quality of zero
+	rejected := func(mediaType string) bool {
+		best := -1
+		quality := 0.0
+		for _, accepted := range ranges {
+			if accepted.matches(mediaType) && accepted.specificity() > best {
+				best, quality = accepted.specificity(), accepted.quality
+			}
+		}
+		return quality == 0
+	}
+
+	mediaTypes := make([]string, 0, len(config.Serializers))
+	for mediaType := range config.Serializers {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	for _, accepted := range ranges {
+		if accepted.quality == 0 {
+			continue
+		}
+		if accepted.matches(config.DefaultMediaType) && !rejected(config.DefaultMediaType) {
+			if _, ok := config.Serializers[config.DefaultMediaType]; ok {
+				return config.DefaultMediaType, true
+			}
+		}
+		for _, mediaType := range mediaTypes {
+			if accepted.matches(mediaType) && !rejected(mediaType) {
+				return mediaType, true
+			}
+		}
+	}
+	return "", false
+}
+
+//withRouter makes the router available to the responses, e.g. to look up the Serializers
+func withRouter(request *http.Request, router *Router) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), routerContextKey{}, router))
+}
+
+//orderedObject is a JSON object, which keeps the order of its keys
+type orderedObject []orderedField
+
+type orderedField struct {
+	key   string
+	value any
+}
+
+//jsonTree marshals the data with encoding/json and decodes it to nil, bool, json.Number, string, []any and orderedObject.
+//This way the serializers of other formats respect json tags and json.Marshaler and keep the order of struct fields.
+func jsonTree(data any) (any, error) {
+	marshaled, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(marshaled))
+	decoder.UseNumber()
+	return decodeJsonTree(decoder)
+}
+
+func decodeJsonTree(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeJsonTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err = decoder.Token()
+		return array, err
+	case json.Delim('{'):
+		object := orderedObject{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJsonTree(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object = append(object, orderedField{key: key.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return object, err
+	}
+	return token, nil
+}
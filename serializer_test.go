@@ -0,0 +1,90 @@
+package there
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+type serialized struct {
+	Name    string            `json:"name"`
+	Version float64           `json:"version"`
+	Tags    []string          `json:"tags"`
+	Owner   *serialized       `json:"owner,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Items   []any             `json:"items"`
+}
+
+func TestMarshalYaml(t *testing.T) {
+	data := serialized{
+		Name:    "there",
+		Version: 2.5,
+		Tags:    []string{"router", "yes", "a: b", ""},
+		Owner:   &serialized{Name: "Gebes", Tags: []string{}},
+		Items:   []any{map[string]int{"id": 1, "count": 2}, []int{1, 2}, nil, true},
+	}
+	yaml, err := MarshalYaml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertEquals(t, string(yaml), `name: there
+version: 2.5
+tags:
+  - router
+  - "yes"
+  - "a: b"
+  - ""
+owner:
+  name: Gebes
+  version: 0
+  tags: []
+  items: null
+items:
+  - count: 2
+    id: 1
+  - - 1
+    - 2
+  - null
+  - true
+`)
+
+	for data, want := range map[any]string{"plain": "plain\n", "12": "\"12\"\n", 12: "12\n", nil: "null\n"} {
+		yaml, _ := MarshalYaml(data)
+		AssertEquals(t, string(yaml), want)
+	}
+	if _, err := MarshalYaml(make(chan int)); err == nil {
+		t.Error("marshaling a channel should fail")
+	}
+}
+
+func TestMarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		data any
+		want string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{5, "05"},
+		{-5, "fb"},
+		{-100, "d09c"},
+		{1000, "d103e8"},
+		{100000, "d2000186a0"},
+		{uint64(1) << 63, "cf8000000000000000"},
+		{1.5, "cb3ff8000000000000"},
+		{"hi", "a26869"},
+		{[]int{1, 2}, "920102"},
+		{struct {
+			A int    `json:"a"`
+			B string `json:"b"`
+		}{1, "x"}, "82a16101a162a178"},
+	}
+	for _, test := range tests {
+		msgpack, err := MarshalMsgpack(test.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		AssertEquals(t, hex.EncodeToString(msgpack), test.want)
+	}
+
+	long, _ := MarshalMsgpack(string(make([]byte, 300)))
+	AssertEquals(t, hex.EncodeToString(long[:3]), "da012c")
+}
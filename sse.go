@@ -0,0 +1,183 @@
+package there
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Event is a single Server-Sent Event. Empty fields are omitted.
+type Event struct {
+	ID    string
+	Event string
+	//Retry tells the client how long to wait before reconnecting
+	Retry time.Duration
+	//Data is written as is for strings and byte slices, everything else is marshaled by the JSON Serializer
+	Data any
+}
+
+//EventStreamer produces the events of an EventStreamFunc response. send fails, once the client disconnected.
+//The stream ends, when the EventStreamer returns.
+type EventStreamer func(ctx context.Context, send func(event Event) error) error
+
+//ErrStreamingUnsupported is rendered, if the http.ResponseWriter cannot flush
+var ErrStreamingUnsupported = errors.New("the response writer does not support streaming")
+
+//LastEventID returns the Last-Event-ID header a reconnecting EventSource sends, to resume after the last event it received
+func (r *HttpRequest) LastEventID() string {
+	return r.Headers.GetDefault(textproto.CanonicalMIMEHeaderKey(RequestHeaderLastEventID), "")
+}
+
+//EventStream sends the events of the channel as Server-Sent Events, until the channel is closed or the client disconnects
+func EventStream(events <-chan Event) HttpResponse {
+	return EventStreamFunc(func(ctx context.Context, send func(event Event) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				if err := send(event); err != nil {
+					return err
+				}
+			}
+		}
+	})
+}
+
+//EventStreamFunc streams the events the streamer sends as Server-Sent Events.
+//Every event is flushed immediately and comments keep the connection alive in the interval of RouterConfiguration.EventStreamKeepAlive.
+//The context of the streamer is cancelled, when the client disconnects or the router shuts down.
+func EventStreamFunc(streamer EventStreamer) HttpResponse {
+	return &eventStreamResponse{streamer: streamer}
+}
+
+type eventStreamResponse struct {
+	streamer EventStreamer
+}
+
+func (e eventStreamResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		Error(StatusInternalServerError, ErrStreamingUnsupported).ServeHTTP(rw, r)
+		return
+	}
+	config := configuration(r)
+
+	header := rw.Header()
+	header.Set(ResponseHeaderContentType, ContentTypeTextEventDashStream)
+	header.Set(ResponseHeaderCacheControl, "no-cache")
+	header.Set(ResponseHeaderConnection, "keep-alive")
+	// disables the response buffering of nginx
+	header.Set("X-Accel-Buffering", "no")
+	rw.WriteHeader(StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	//the stream ends as well, when the router shuts down
+	if router, ok := r.Context().Value(routerContextKey{}).(*Router); ok && router.closing != nil {
+		go func() {
+			select {
+			case <-router.closing.Done():
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	var mutex sync.Mutex
+	write := func(frame string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := rw.Write([]byte(frame)); err != nil {
+			cancel()
+			return err
+		}
+		flusher.Flush()
+		return nil
+	}
+
+	var wait sync.WaitGroup
+	if config.EventStreamKeepAlive > 0 {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			ticker := time.NewTicker(config.EventStreamKeepAlive)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if write(": keep-alive\n\n") != nil {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	_ = e.streamer(ctx, func(event Event) error {
+		frame, err := event.frame(config)
+		if err != nil {
+			return err
+		}
+		return write(frame)
+	})
+	cancel()
+	wait.Wait()
+}
+
+var eventFieldSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+//frame formats the event in the text/event-stream format
+func (e Event) frame(config *RouterConfiguration) (string, error) {
+	var data string
+	switch d := e.Data.(type) {
+	case nil:
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		serializer, ok := config.Serializers[ContentTypeApplicationJson]
+		if !ok {
+			return "", errors.New("there is no serializer for the media type " + ContentTypeApplicationJson)
+		}
+		marshaled, err := serializer(d)
+		if err != nil {
+			return "", err
+		}
+		data = string(marshaled)
+	}
+
+	var frame strings.Builder
+	if e.ID != "" {
+		frame.WriteString("id: " + eventFieldSanitizer.Replace(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		frame.WriteString("event: " + eventFieldSanitizer.Replace(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		frame.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	if e.Data != nil {
+		data = strings.ReplaceAll(data, "\r\n", "\n")
+		for _, line := range strings.Split(data, "\n") {
+			frame.WriteString("data: " + line + "\n")
+		}
+	}
+	frame.WriteString("\n")
+	return frame.String(), nil
+}
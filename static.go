@@ -0,0 +1,263 @@
+package there
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+//staticPathParam is the name of the wildcard route param, which captures the file path below a static prefix
+const staticPathParam = "staticPath"
+
+//StaticConfiguration adjusts how StaticWithConfiguration serves a directory
+type StaticConfiguration struct {
+	//IndexFiles are served instead of a directory, the first existing one wins
+	IndexFiles []string
+	//Browse lists the content of directories without an index file
+	Browse bool
+	//SinglePageApp serves the first index file of the root for every path which does not exist,
+	//so the router of the single page app can handle it
+	SinglePageApp bool
+}
+
+//DefaultStaticConfiguration serves index.html for directories and does not list them
+func DefaultStaticConfiguration() StaticConfiguration {
+	return StaticConfiguration{
+		IndexFiles: []string{"index.html"},
+	}
+}
+
+//Static serves the files of root under prefix with DefaultStaticConfiguration.
+//root is either the path of a directory or a fs.FS like embed.FS:
+//
+//	//go:embed public
+//	var public embed.FS
+//
+//	router.Static("/assets", "./assets")
+//	router.Static("/", public)
+//
+//Range, If-Range and If-Modified-Since requests are supported and the Content-Type is derived from the extension.
+//Paths which do not exist are answered by RouterConfiguration.RouteNotFoundHandler.
+func (group *RouteGroup) Static(prefix string, root any) *RouteRouteGroupBuilder {
+	return group.StaticWithConfiguration(prefix, root, DefaultStaticConfiguration())
+}
+
+//StaticWithConfiguration works like Static, but the StaticConfiguration enables directory listings or the single page app mode
+func (group *RouteGroup) StaticWithConfiguration(prefix string, root any, config StaticConfiguration) *RouteRouteGroupBuilder {
+	var fsys fs.FS
+	switch r := root.(type) {
+	case string:
+		fsys = os.DirFS(r)
+	case fs.FS:
+		fsys = r
+	default:
+		panic("static root must be a directory path or a fs.FS")
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	return group.Get(prefix+"/*"+staticPathParam, func(request HttpRequest) HttpResponse {
+		return &staticResponse{
+			fsys:   fsys,
+			name:   cleanFileName(request.RouteParams.GetDefault(staticPathParam, "")),
+			config: config,
+			//the handler is only invoked for paths which do not exist
+			notFound: HttpResponseFunc(func(rw http.ResponseWriter, r *http.Request) {
+				group.Configuration.RouteNotFoundHandler(request).ServeHTTP(rw, r)
+			}),
+		}
+	})
+}
+
+//cleanFileName converts the path of a request to a name accepted by fs.FS
+func cleanFileName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
+
+type staticResponse struct {
+	fsys     fs.FS
+	name     string
+	config   StaticConfiguration
+	notFound HttpResponse
+}
+
+func (s staticResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	info, err := fs.Stat(s.fsys, s.name)
+	if err != nil {
+		if s.config.SinglePageApp && errors.Is(err, fs.ErrNotExist) && s.serveIndex(rw, r, ".") {
+			return
+		}
+		s.fileError(rw, r, err)
+		return
+	}
+	if !info.IsDir() {
+		serveFile(rw, r, s.fsys, s.name)
+		return
+	}
+
+	if !strings.HasSuffix(r.URL.Path, "/") {
+		target := url.URL{Path: r.URL.Path + "/", RawQuery: r.URL.RawQuery}
+		Redirect(StatusMovedPermanently, target.String()).ServeHTTP(rw, r)
+		return
+	}
+	if s.serveIndex(rw, r, s.name) {
+		return
+	}
+	if !s.config.Browse {
+		s.notFound.ServeHTTP(rw, r)
+		return
+	}
+	entries, err := fs.ReadDir(s.fsys, s.name)
+	if err != nil {
+		s.fileError(rw, r, err)
+		return
+	}
+	WithHeaders(MapString{
+		ResponseHeaderContentType: ContentTypeTextHtml,
+	}, String(StatusOK, directoryListing(r.URL.Path, entries))).ServeHTTP(rw, r)
+}
+
+//serveIndex serves the first index file of the directory and returns false, if there is none
+func (s staticResponse) serveIndex(rw http.ResponseWriter, r *http.Request, dir string) bool {
+	for _, index := range s.config.IndexFiles {
+		name := path.Join(dir, index)
+		if info, err := fs.Stat(s.fsys, name); err == nil && !info.IsDir() {
+			serveFile(rw, r, s.fsys, name)
+			return true
+		}
+	}
+	return false
+}
+
+func (s staticResponse) fileError(rw http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		s.notFound.ServeHTTP(rw, r)
+		return
+	}
+	fileError(err).ServeHTTP(rw, r)
+}
+
+func directoryListing(urlPath string, entries []fs.DirEntry) string {
+	title := html.EscapeString("Index of " + urlPath)
+	var listing strings.Builder
+	listing.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>" + title + "</title></head>\n<body>\n<h1>" + title + "</h1>\n<ul>\n")
+	if urlPath != "/" {
+		listing.WriteString("<li><a href=\"../\">../</a></li>\n")
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		link := url.URL{Path: name}
+		listing.WriteString("<li><a href=\"" + html.EscapeString(link.String()) + "\">" + html.EscapeString(name) + "</a></li>\n")
+	}
+	listing.WriteString("</ul>\n</body>\n</html>\n")
+	return listing.String()
+}
+
+//serveFile serves the file of fsys with support for Range, If-Range and If-Modified-Since requests
+func serveFile(rw http.ResponseWriter, r *http.Request, fsys fs.FS, name string) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		fileError(err).ServeHTTP(rw, r)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		fileError(err).ServeHTTP(rw, r)
+		return
+	}
+	if info.IsDir() {
+		Error(StatusNotFound, errors.New(name+" is a directory")).ServeHTTP(rw, r)
+		return
+	}
+
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			Error(StatusInternalServerError, err).ServeHTTP(rw, r)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+	serveContent(rw, r, info.Name(), info.ModTime(), content)
+}
+
+//fileError renders StatusNotFound for files which do not exist and StatusInternalServerError otherwise
+func fileError(err error) HttpResponse {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		return Error(StatusNotFound, err)
+	}
+	return Error(StatusInternalServerError, err)
+}
+
+//serveContent sets the Content-Type for the extension of the name, if it is not set yet, and serves the content
+func serveContent(rw http.ResponseWriter, r *http.Request, name string, modTime time.Time, content io.ReadSeeker) {
+	if rw.Header().Get(ResponseHeaderContentType) == "" {
+		if contentType := ContentTypeByExtension(filepath.Ext(name)); contentType != "" {
+			rw.Header().Set(ResponseHeaderContentType, contentType)
+		}
+	}
+	http.ServeContent(rw, r, name, modTime, content)
+}
+
+var contentTypes = map[string]string{
+	".css":   ContentTypeTextCss,
+	".csv":   ContentTypeTextCsv,
+	".djvu":  ContentTypeImageVndDotDjvu,
+	".flv":   ContentTypeVideoXDashFlv,
+	".gif":   ContentTypeImageGif,
+	".htm":   ContentTypeTextHtml,
+	".html":  ContentTypeTextHtml,
+	".ico":   ContentTypeImageVndDotMicrosoftDotIcon,
+	".jar":   ContentTypeApplicationJavaDashArchive,
+	".jpeg":  ContentTypeImageJpeg,
+	".jpg":   ContentTypeImageJpeg,
+	".js":    ContentTypeTextJavascript,
+	".json":  ContentTypeApplicationJson,
+	".mjs":   ContentTypeTextJavascript,
+	".mov":   ContentTypeVideoQuicktime,
+	".mp3":   ContentTypeAudioMpeg,
+	".mp4":   ContentTypeVideoMp4,
+	".mpeg":  ContentTypeVideoMpeg,
+	".ogg":   ContentTypeApplicationOgg,
+	".pdf":   ContentTypeApplicationPdf,
+	".png":   ContentTypeImagePng,
+	".svg":   ContentTypeImageSvgPlusXml,
+	".tif":   ContentTypeImageTiff,
+	".tiff":  ContentTypeImageTiff,
+	".txt":   ContentTypeTextPlain,
+	".wav":   ContentTypeAudioXDashWav,
+	".webm":  ContentTypeVideoWebm,
+	".wma":   ContentTypeAudioXDashMsDashWma,
+	".wmv":   ContentTypeVideoXDashMsDashWmv,
+	".xhtml": ContentTypeApplicationXhtmlPlusXml,
+	".xml":   ContentTypeApplicationXml,
+	".yaml":  ContentTypeApplicationYaml,
+	".yml":   ContentTypeApplicationYaml,
+	".zip":   ContentTypeApplicationZip,
+}
+
+//ContentTypeByExtension returns the ContentType constant for the file extension, e.g. ".html".
+//Unknown extensions are looked up with mime.TypeByExtension, an empty string is returned if it does not know them either.
+func ContentTypeByExtension(extension string) string {
+	if contentType, ok := contentTypes[strings.ToLower(extension)]; ok {
+		return contentType
+	}
+	return mime.TypeByExtension(extension)
+}
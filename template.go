@@ -0,0 +1,257 @@
+package there
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+//TemplateEngine parses the html templates of a directory or fs.FS once and renders them by name.
+//Files in the layouts and partials directories are available in every page:
+//
+//	layouts/base.html   {{define "base"}}<html><body>{{block "content" .}}{{end}}</body></html>{{end}}
+//	partials/nav.html   {{define "nav"}}<nav>...</nav>{{end}}
+//	users/show.html     {{template "base" .}}{{define "content"}}{{template "nav"}}{{.Name}}{{end}}{{end}}
+//
+//Every page is parsed together with its own copy of the layouts and partials, so pages can redefine the same blocks.
+//Pages are identified by their path, e.g. "users/show.html" or "users/show".
+type TemplateEngine struct {
+	fsys        fs.FS
+	extension   string
+	layoutsDir  string
+	partialsDir string
+	funcs       template.FuncMap
+	reload      bool
+
+	mutex sync.RWMutex
+	//shared contains the layouts and partials, templates every page
+	shared    *template.Template
+	templates map[string]*template.Template
+	//modified is the latest modification time of the parsed files, used to detect changes in reload mode
+	modified time.Time
+}
+
+//NewTemplateEngine creates a TemplateEngine for the .html files of root, which is either the path of a directory or a fs.FS like embed.FS.
+//Layouts are read from the directory "layouts" and partials from "partials".
+func NewTemplateEngine(root any) *TemplateEngine {
+	var fsys fs.FS
+	switch r := root.(type) {
+	case string:
+		fsys = os.DirFS(r)
+	case fs.FS:
+		fsys = r
+	default:
+		panic("template root must be a directory path or a fs.FS")
+	}
+	return &TemplateEngine{
+		fsys:        fsys,
+		extension:   ".html",
+		layoutsDir:  "layouts",
+		partialsDir: "partials",
+		funcs:       template.FuncMap{},
+	}
+}
+
+//Extension sets the extension of the template files, ".html" by default
+func (e *TemplateEngine) Extension(extension string) *TemplateEngine {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.extension = extension
+	e.templates = nil
+	return e
+}
+
+//Directories sets the directories of the layouts and partials, relative to the root
+func (e *TemplateEngine) Directories(layouts, partials string) *TemplateEngine {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.layoutsDir = strings.Trim(layouts, "/")
+	e.partialsDir = strings.Trim(partials, "/")
+	e.templates = nil
+	return e
+}
+
+//Funcs makes the functions available in every template. Existing functions with the same name are replaced.
+func (e *TemplateEngine) Funcs(funcs template.FuncMap) *TemplateEngine {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for name, fn := range funcs {
+		e.funcs[name] = fn
+	}
+	e.templates = nil
+	return e
+}
+
+//Reload enables the development mode, in which the templates are parsed again as soon as a file changed
+func (e *TemplateEngine) Reload(enabled bool) *TemplateEngine {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.reload = enabled
+	return e
+}
+
+//Load parses every template. It is called by Router.Templates before the router starts listening,
+//so broken templates prevent the start instead of failing the first request.
+func (e *TemplateEngine) Load() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.load()
+}
+
+//Render executes the page or partial with the given name and writes it to w
+func (e *TemplateEngine) Render(w io.Writer, name string, data any) error {
+	t, err := e.lookup(name)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, data)
+}
+
+//lookup returns the template set containing the name, the name is completed with the extension if necessary
+func (e *TemplateEngine) lookup(name string) (*template.Template, error) {
+	e.mutex.RLock()
+	loaded := e.templates != nil && !(e.reload && e.changed())
+	e.mutex.RUnlock()
+	if !loaded {
+		e.mutex.Lock()
+		if e.templates == nil || (e.reload && e.changed()) {
+			if err := e.load(); err != nil {
+				e.mutex.Unlock()
+				return nil, err
+			}
+		}
+		e.mutex.Unlock()
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	for _, candidate := range []string{name, name + e.extension} {
+		if t, ok := e.templates[candidate]; ok {
+			return t.Lookup(candidate), nil
+		}
+		if t := e.shared.Lookup(candidate); t != nil {
+			return t, nil
+		}
+	}
+	return nil, errors.New("there is no template with the name \"" + name + "\"")
+}
+
+//load parses the layouts and partials and every page with a copy of them. The mutex must be locked.
+func (e *TemplateEngine) load() error {
+	var shared, pages []string
+	var modified time.Time
+	err := fs.WalkDir(e.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != e.extension {
+			return err
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		if isInDirectory(name, e.layoutsDir) || isInDirectory(name, e.partialsDir) {
+			shared = append(shared, name)
+		} else {
+			pages = append(pages, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	base := template.New("").Funcs(e.funcs)
+	for _, name := range shared {
+		if err = e.parseFile(base, name); err != nil {
+			return err
+		}
+	}
+	templates := make(map[string]*template.Template, len(pages))
+	for _, name := range pages {
+		page, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		if err = e.parseFile(page, name); err != nil {
+			return err
+		}
+		templates[name] = page
+	}
+
+	e.shared = base
+	e.templates = templates
+	e.modified = modified
+	return nil
+}
+
+func (e *TemplateEngine) parseFile(set *template.Template, name string) error {
+	content, err := fs.ReadFile(e.fsys, name)
+	if err != nil {
+		return err
+	}
+	_, err = set.New(name).Parse(string(content))
+	return err
+}
+
+var errTemplateChanged = errors.New("template changed")
+
+//changed checks if a template file was modified after the templates were parsed
+func (e *TemplateEngine) changed() bool {
+	err := fs.WalkDir(e.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(name) != e.extension {
+			return err
+		}
+		if info, err := entry.Info(); err == nil && info.ModTime().After(e.modified) {
+			return errTemplateChanged
+		}
+		return nil
+	})
+	return errors.Is(err, errTemplateChanged)
+}
+
+func isInDirectory(name, dir string) bool {
+	return dir != "" && strings.HasPrefix(name, dir+"/")
+}
+
+//Templates configures the TemplateEngine used by HtmlTemplate. The functions of Router.TemplateFuncs are registered
+//and the templates are loaded before the router starts listening.
+func (router *Router) Templates(engine *TemplateEngine) *Router {
+	engine.Funcs(router.TemplateFuncs())
+	router.Configuration.TemplateEngine = engine
+	return router.OnStart(engine.Load)
+}
+
+//ErrNoTemplateEngine is the panic of HtmlTemplate, if the router has no TemplateEngine, see Router.Templates
+var ErrNoTemplateEngine = errors.New("there is no template engine configured")
+
+//HtmlTemplate takes a StatusCode and renders the page or partial with the given name of the RouterConfiguration.TemplateEngine.
+//The template is rendered completely before anything is written, errors cause a panic like Html does.
+func HtmlTemplate(code int, name string, data any) HttpResponse {
+	return &htmlTemplateResponse{code: code, name: name, data: data}
+}
+
+type htmlTemplateResponse struct {
+	code int
+	name string
+	data any
+}
+
+func (h htmlTemplateResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	engine := configuration(r).TemplateEngine
+	if engine == nil {
+		panic(ErrNoTemplateEngine)
+	}
+	buffer := new(bytes.Buffer)
+	if err := engine.Render(buffer, h.name, h.data); err != nil {
+		panic(err)
+	}
+	WithHeaders(MapString{
+		ResponseHeaderContentType: ContentTypeTextHtml,
+	}, Bytes(h.code, buffer.Bytes())).ServeHTTP(rw, r)
+}
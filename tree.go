@@ -0,0 +1,294 @@
+package there
+
+import (
+	"sort"
+	"strings"
+)
+
+//routeTree resolves a request path to its Routes in time proportional to the depth of the path.
+//Routes which ignore the case are stored in their own tree with lowercase static segments.
+type routeTree struct {
+	root           *node
+	rootIgnoreCase *node
+}
+
+func newRouteTree() *routeTree {
+	return &routeTree{
+		root:           newNode(nil),
+		rootIgnoreCase: newNode(nil),
+	}
+}
+
+//node is a vertex of the compressed prefix tree. Static segments without
+//branches in between are merged into a single node.
+type node struct {
+	segments []string
+	static   map[string]*node
+	//params are the children matching a single segment, the constrained ones first
+	params []*node
+	//wildcards are the children matching the remaining segments, the constrained ones first
+	wildcards []*node
+	//part is the variable part a param or wildcard node was created for
+	part   pathPart
+	routes []*Route
+}
+
+func newNode(segments []string) *node {
+	return &node{
+		segments: segments,
+		static:   map[string]*node{},
+	}
+}
+
+func (t *routeTree) rootFor(path Path) *node {
+	if path.ignoreCase {
+		return t.rootIgnoreCase
+	}
+	return t.root
+}
+
+//Insert adds the route to the node its Path ends at
+func (t *routeTree) Insert(route *Route) {
+	current := t.rootFor(route.Path)
+	parts := route.Path.parts
+	for len(parts) > 0 {
+		if parts[0].variable {
+			current = current.variableChild(parts[0])
+			parts = parts[1:]
+			continue
+		}
+
+		run := staticRun(parts, route.Path.ignoreCase)
+		child, ok := current.static[run[0]]
+		if !ok {
+			child = newNode(run)
+			current.static[run[0]] = child
+		}
+		common := commonSegments(child.segments, run)
+		if common < len(child.segments) {
+			child.split(common)
+		}
+		current = child
+		parts = parts[common:]
+	}
+	current.routes = append(current.routes, route)
+}
+
+//variableChild returns the param or wildcard child for the constraint of the part and creates it if necessary
+func (n *node) variableChild(part pathPart) *node {
+	children := &n.params
+	if part.wildcard {
+		children = &n.wildcards
+	}
+	if child := childFor(*children, part); child != nil {
+		return child
+	}
+	child := newNode(nil)
+	child.part = part
+	*children = append(*children, child)
+	sort.SliceStable(*children, func(i, j int) bool {
+		return (*children)[i].part.constraint != "" && (*children)[j].part.constraint == ""
+	})
+	return child
+}
+
+//childFor returns the child with the constraint of the part or nil
+func childFor(children []*node, part pathPart) *node {
+	for _, child := range children {
+		if child.part.constraint == part.constraint {
+			return child
+		}
+	}
+	return nil
+}
+
+//Remove deletes the route from the node its Path ends at
+func (t *routeTree) Remove(route *Route) {
+	n := t.rootFor(route.Path).find(route.Path)
+	if n == nil {
+		return
+	}
+	for i, r := range n.routes {
+		if r == route {
+			n.routes = append(n.routes[:i], n.routes[i+1:]...)
+			return
+		}
+	}
+}
+
+//Find returns the Route for the segments and method together with its route params.
+//Routes which respect the case take precedence over the ones ignoring it.
+func (t *routeTree) Find(segments []string, method string) (*Route, map[string]string) {
+	var found *Route
+	visit := func(n *node) bool {
+		for _, route := range n.routes {
+			if CheckArrayContains(route.Methods, method) {
+				found = route
+				return true
+			}
+		}
+		return false
+	}
+
+	if t.root.lookup(segments, false, visit) || t.rootIgnoreCase.lookup(segments, true, visit) {
+		return found, found.Path.params(segments)
+	}
+	return nil, nil
+}
+
+//AllowedMethods returns the methods of every Route matching the segments, ordered like AllMethods
+func (t *routeTree) AllowedMethods(segments []string) []string {
+	found := map[string]bool{}
+	visit := func(n *node) bool {
+		for _, route := range n.routes {
+			for _, method := range route.Methods {
+				found[method] = true
+			}
+		}
+		return false
+	}
+	t.root.lookup(segments, false, visit)
+	t.rootIgnoreCase.lookup(segments, true, visit)
+
+	methods := make([]string, 0, len(found))
+	for method := range found {
+		methods = append(methods, method)
+	}
+	return sortMethods(methods)
+}
+
+//sortMethods orders the methods like AllMethods, followed by custom methods in alphabetical order
+func sortMethods(methods []string) []string {
+	index := func(method string) int {
+		for i, m := range AllMethods {
+			if m == method {
+				return i
+			}
+		}
+		return len(AllMethods)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		a, b := index(methods[i]), index(methods[j])
+		if a != b {
+			return a < b
+		}
+		return methods[i] < methods[j]
+	})
+	return methods
+}
+
+//lookup walks every node matching the segments, static segments before route params
+//before wildcards, and stops as soon as visit returns true
+func (n *node) lookup(segments []string, ignoreCase bool, visit func(n *node) bool) bool {
+	if len(segments) == 0 {
+		if len(n.routes) != 0 && visit(n) {
+			return true
+		}
+	} else {
+		key := segments[0]
+		if ignoreCase {
+			key = strings.ToLower(key)
+		}
+		if child, ok := n.static[key]; ok && hasSegmentPrefix(segments, child.segments, ignoreCase) {
+			if child.lookup(segments[len(child.segments):], ignoreCase, visit) {
+				return true
+			}
+		}
+		for _, param := range n.params {
+			if param.part.matches(segments[0]) && param.lookup(segments[1:], ignoreCase, visit) {
+				return true
+			}
+		}
+	}
+	for _, wildcard := range n.wildcards {
+		if len(wildcard.routes) != 0 && wildcard.part.matches(strings.Join(segments, "/")) && visit(wildcard) {
+			return true
+		}
+	}
+	return false
+}
+
+//find returns the node the path ends at, without matching route params against static segments
+func (n *node) find(path Path) *node {
+	current := n
+	parts := path.parts
+	for len(parts) > 0 {
+		if parts[0].wildcard {
+			return childFor(current.wildcards, parts[0])
+		}
+		if parts[0].variable {
+			current = childFor(current.params, parts[0])
+			if current == nil {
+				return nil
+			}
+			parts = parts[1:]
+			continue
+		}
+		run := staticRun(parts, path.ignoreCase)
+		child, ok := current.static[run[0]]
+		if !ok || !hasSegmentPrefix(run, child.segments, false) {
+			return nil
+		}
+		current = child
+		parts = parts[len(child.segments):]
+	}
+	return current
+}
+
+//split cuts the node after the given amount of segments and moves the rest into a new child
+func (n *node) split(at int) {
+	child := &node{
+		segments:  n.segments[at:],
+		static:    n.static,
+		params:    n.params,
+		wildcards: n.wildcards,
+		routes:    n.routes,
+	}
+	n.segments = n.segments[:at]
+	n.static = map[string]*node{child.segments[0]: child}
+	n.params = nil
+	n.wildcards = nil
+	n.routes = nil
+}
+
+//staticRun returns the leading static segments of parts
+func staticRun(parts []pathPart, ignoreCase bool) []string {
+	run := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.variable {
+			break
+		}
+		value := part.value
+		if ignoreCase {
+			value = strings.ToLower(value)
+		}
+		run = append(run, value)
+	}
+	return run
+}
+
+func commonSegments(a, b []string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+//hasSegmentPrefix checks if segments start with prefix. If ignoreCase is set,
+//the prefix must already be in lowercase.
+func hasSegmentPrefix(segments, prefix []string, ignoreCase bool) bool {
+	if len(segments) < len(prefix) {
+		return false
+	}
+	for i, p := range prefix {
+		s := segments[i]
+		if ignoreCase {
+			s = strings.ToLower(s)
+		}
+		if s != p {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package there
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouteTree_Find(t *testing.T) {
+	router := NewRouter()
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+
+	router.Get("/users/me", handler)
+	router.Get("/users/:id", handler)
+	router.Get("/users/:id/posts", handler)
+	router.Get("/users/me/settings", handler)
+	router.Post("/users/me/posts", handler)
+	router.Get("/api/v1/status", handler)
+	router.Get("/api/v2/status", handler)
+	router.Get("/Files/:name", handler).IgnoreCase()
+	router.Get("/static/*filepath", handler)
+	router.Get("/static/:file", handler)
+	router.Get("/static/index.html", handler)
+	router.Get("/orders/:id<int>", handler)
+	router.Get("/orders/:id<uuid>", handler)
+	router.Get("/orders/:name", handler)
+	router.Get("/", handler)
+
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+		params map[string]string
+	}{
+		{name: "root", method: MethodGet, url: "/", want: "/", params: map[string]string{}},
+		{name: "static wins", method: MethodGet, url: "/users/me", want: "/users/me", params: map[string]string{}},
+		{name: "param", method: MethodGet, url: "/users/42", want: "/users/:id", params: map[string]string{"id": "42"}},
+		{name: "backtrack to param", method: MethodGet, url: "/users/me/posts", want: "/users/:id/posts", params: map[string]string{"id": "me"}},
+		{name: "static method", method: MethodPost, url: "/users/me/posts", want: "/users/me/posts", params: map[string]string{}},
+		{name: "deep static", method: MethodGet, url: "/users/me/settings", want: "/users/me/settings", params: map[string]string{}},
+		{name: "split node", method: MethodGet, url: "/api/v2/status", want: "/api/v2/status", params: map[string]string{}},
+		{name: "ignore case", method: MethodGet, url: "/FILES/Readme", want: "/Files/:name", params: map[string]string{"name": "Readme"}},
+		{name: "wildcard", method: MethodGet, url: "/static/css/main.css", want: "/static/*filepath", params: map[string]string{"filepath": "css/main.css"}},
+		{name: "empty wildcard", method: MethodGet, url: "/static/", want: "/static/*filepath", params: map[string]string{"filepath": ""}},
+		{name: "param before wildcard", method: MethodGet, url: "/static/main.css", want: "/static/:file", params: map[string]string{"file": "main.css"}},
+		{name: "static before wildcard", method: MethodGet, url: "/static/index.html", want: "/static/index.html", params: map[string]string{}},
+		{name: "int constraint", method: MethodGet, url: "/orders/12", want: "/orders/:id<int>", params: map[string]string{"id": "12"}},
+		{name: "uuid constraint", method: MethodGet, url: "/orders/123e4567-e89b-12d3-a456-426614174000", want: "/orders/:id<uuid>", params: map[string]string{"id": "123e4567-e89b-12d3-a456-426614174000"}},
+		{name: "unconstrained fallback", method: MethodGet, url: "/orders/latest", want: "/orders/:name", params: map[string]string{"name": "latest"}},
+		{name: "partial static", method: MethodGet, url: "/api/v1", want: ""},
+		{name: "wrong method", method: MethodDelete, url: "/users/me", want: ""},
+		{name: "too long", method: MethodGet, url: "/users/42/posts/1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, params := router.tree.Find(splitUrl(tt.url), tt.method)
+			if route == nil {
+				if tt.want != "" {
+					t.Errorf("Find() found no route, want %v", tt.want)
+				}
+				return
+			}
+			if got := route.Path.ToString(); got != tt.want {
+				t.Errorf("Find() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("Find() params = %v, want %v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestRouteTree_FindConstrainedWildcards(t *testing.T) {
+	router := NewRouter()
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+	//the constrained wildcard must win in both registration orders
+	router.Get(`/f/*p<.+\.css>`, handler)
+	router.Get("/f/*q", handler)
+	router.Get("/g/*q", handler)
+	router.Get(`/g/*p<.+\.css>`, handler)
+
+	tests := map[string]string{
+		"/f/a.js":      "/f/*q",
+		"/f/a.css":     `/f/*p<.+\.css>`,
+		"/g/a.js":      "/g/*q",
+		"/g/dir/a.css": `/g/*p<.+\.css>`,
+	}
+	for url, want := range tests {
+		route, _ := router.tree.Find(splitUrl(url), MethodGet)
+		if route == nil {
+			t.Errorf("Find(%v) found no route, want %v", url, want)
+			continue
+		}
+		if got := route.Path.ToString(); got != want {
+			t.Errorf("Find(%v) = %v, want %v", url, got, want)
+		}
+	}
+
+	route := router.Get("/h/*q", handler).Route
+	router.tree.Remove(route)
+	if found, _ := router.tree.Find(splitUrl("/h/a"), MethodGet); found != nil {
+		t.Errorf("Find() = %v, want nil", found.ToString())
+	}
+}
+
+func TestRouteTree_Remove(t *testing.T) {
+	router := NewRouter()
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+	route := router.Get("/home", handler).Route
+	router.tree.Remove(route)
+
+	if found, _ := router.tree.Find(splitUrl("/home"), MethodGet); found != nil {
+		t.Errorf("Find() = %v, want nil", found.ToString())
+	}
+}
+
+func TestRouteTree_AllowedMethods(t *testing.T) {
+	router := NewRouter()
+	handler := func(request HttpRequest) HttpResponse {
+		return Status(StatusOK)
+	}
+	router.Post("/users/:id", handler)
+	router.Handle("/users/:id", handler, MethodPut, "PURGE")
+	router.Get("/users/me", handler)
+	router.Delete("/USERS/me", handler).IgnoreCase()
+
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{name: "union of param and static", url: "/users/me", want: []string{MethodGet, MethodPost, MethodPut, MethodDelete, "PURGE"}},
+		{name: "param only", url: "/users/42", want: []string{MethodPost, MethodPut, "PURGE"}},
+		{name: "no route", url: "/posts", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := router.tree.AllowedMethods(splitUrl(tt.url)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AllowedMethods() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package there
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+//ValidationError describes a single rule a field did not satisfy
+type ValidationError struct {
+	//Field is the JSON path of the field, e.g. address.city or items[2].name
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+//ValidationErrors is returned by Validate and the bind functions, if at least one rule failed
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, e := range v {
+		messages[i] = e.Field + " " + e.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+//Fields groups the messages by the JSON path of the field
+func (v ValidationErrors) Fields() map[string][]string {
+	fields := map[string][]string{}
+	for _, e := range v {
+		fields[e.Field] = append(fields[e.Field], e.Message)
+	}
+	return fields
+}
+
+//Validate checks the validate struct tags of v, which must be a struct or a pointer to one.
+//Nested structs and slices of structs are validated as well. The rules are separated by commas:
+//
+//	type User struct {
+//		Name  string `json:"name" validate:"required,min=3"`
+//		Email string `json:"email" validate:"required,email"`
+//		Role  string `json:"role" validate:"oneof=admin user"`
+//		Tags  []Tag  `json:"tags" validate:"max=5"`
+//	}
+//
+//Supported rules are required, omitempty, min, max and len (value for numbers, length otherwise),
+//oneof, email, url, uuid, alpha, alnum and numeric. Zero values are only skipped with omitempty,
+//nil pointers are always skipped, unless they are required.
+//Returns ValidationErrors or nil.
+func Validate(v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationErrors
+	validateStruct(value, "", &errs)
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(value reflect.Value, prefix string, errs *ValidationErrors) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := jsonFieldName(field)
+		if name == "" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			path = prefix
+		}
+
+		fieldValue := value.Field(i)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if !validateField(fieldValue, path, tag, errs) {
+				continue
+			}
+		}
+		validateNested(fieldValue, path, errs)
+	}
+}
+
+//validateNested descends into structs and slices of structs
+func validateNested(value reflect.Value, path string, errs *ValidationErrors) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() != timeType {
+			validateStruct(value, path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateNested(value.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	}
+}
+
+//validateField checks every rule of the tag and returns false, if the field should not be descended into
+func validateField(value reflect.Value, path, tag string, errs *ValidationErrors) bool {
+	rules := strings.Split(tag, ",")
+	if value.IsZero() {
+		if CheckArrayContains(rules, "required") {
+			*errs = append(*errs, ValidationError{Field: path, Rule: "required", Message: "is required"})
+			return false
+		}
+		if CheckArrayContains(rules, "omitempty") {
+			return false
+		}
+	}
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "required" || name == "omitempty" || name == "" {
+			continue
+		}
+		check, ok := validationRules[name]
+		if !ok {
+			panic("unknown validation rule \"" + name + "\" for " + path)
+		}
+		if message := check(value, param); message != "" {
+			*errs = append(*errs, ValidationError{Field: path, Rule: name, Param: param, Message: message})
+		}
+	}
+	return true
+}
+
+//validationRules return an empty string, if the value satisfies the rule and an error message otherwise
+var validationRules = map[string]func(value reflect.Value, param string) string{
+	"min": func(value reflect.Value, param string) string {
+		return compareSize(value, param, func(size, limit float64) bool { return size >= limit }, "must be at least ", "must have at least ")
+	},
+	"max": func(value reflect.Value, param string) string {
+		return compareSize(value, param, func(size, limit float64) bool { return size <= limit }, "must be at most ", "must have at most ")
+	},
+	"len": func(value reflect.Value, param string) string {
+		return compareSize(value, param, func(size, limit float64) bool { return size == limit }, "must be ", "must have exactly ")
+	},
+	"oneof": func(value reflect.Value, param string) string {
+		options := strings.Fields(param)
+		if !CheckArrayContains(options, stringValue(value)) {
+			return "must be one of " + strings.Join(options, ", ")
+		}
+		return ""
+	},
+	"email": func(value reflect.Value, param string) string {
+		s := stringValue(value)
+		address, err := mail.ParseAddress(s)
+		if err != nil || address.Address != s {
+			return "must be a valid email address"
+		}
+		return ""
+	},
+	"url": func(value reflect.Value, param string) string {
+		u, err := url.ParseRequestURI(stringValue(value))
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid URL"
+		}
+		return ""
+	},
+	"uuid":    matchRule(constraintTypes["uuid"], "must be a valid UUID"),
+	"alpha":   matchRule(constraintTypes["alpha"], "must only contain letters"),
+	"alnum":   matchRule(constraintTypes["alnum"], "must only contain letters and digits"),
+	"numeric": matchRule(constraintTypes["float"], "must be numeric"),
+}
+
+func matchRule(expression, message string) func(value reflect.Value, param string) string {
+	matcher := regexp.MustCompile("^(?:" + expression + ")$")
+	return func(value reflect.Value, param string) string {
+		if !matcher.MatchString(stringValue(value)) {
+			return message
+		}
+		return ""
+	}
+}
+
+//compareSize compares numbers by their value and strings, slices and maps by their length
+func compareSize(value reflect.Value, param string, ok func(size, limit float64) bool, valueMessage, lengthMessage string) string {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic("validation rule parameter \"" + param + "\" is not a number")
+	}
+	var size float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = value.Float()
+	case reflect.String:
+		if !ok(float64(utf8.RuneCountInString(value.String())), limit) {
+			return lengthMessage + param + " characters"
+		}
+		return ""
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if !ok(float64(value.Len()), limit) {
+			return lengthMessage + param + " items"
+		}
+		return ""
+	default:
+		return "cannot be compared with " + param
+	}
+	if !ok(size, limit) {
+		return valueMessage + param
+	}
+	return ""
+}
+
+func stringValue(value reflect.Value) string {
+	if value.Kind() == reflect.String {
+		return value.String()
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+//jsonFieldName returns the name of the field in JSON or an empty string, if it is omitted
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,66 @@
+package there
+
+import (
+	"reflect"
+	"testing"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"omitempty,len=4,numeric"`
+}
+
+type validateItem struct {
+	Name     string `json:"name" validate:"required,alpha"`
+	Quantity int    `json:"quantity" validate:"min=1,max=10"`
+}
+
+type validateOrder struct {
+	Email    string           `json:"email" validate:"required,email"`
+	Role     string           `json:"role" validate:"oneof=admin user"`
+	Website  *string          `json:"website" validate:"url"`
+	Address  validateAddress  `json:"address"`
+	Items    []validateItem   `json:"items" validate:"required,max=3"`
+	Shipping *validateAddress `json:"shipping,omitempty"`
+	ignored  string           `validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+	valid := validateOrder{
+		Email:   "hannes@example.com",
+		Role:    "admin",
+		Address: validateAddress{City: "Vienna", Zip: "1010"},
+		Items:   []validateItem{{Name: "apple", Quantity: 2}},
+	}
+	if err := Validate(&valid); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	website := "not a url"
+	invalid := validateOrder{
+		Email:    "hannes",
+		Role:     "guest",
+		Website:  &website,
+		Address:  validateAddress{Zip: "10"},
+		Items:    []validateItem{{Name: "apple", Quantity: 2}, {Name: "b4n4n4", Quantity: 0}},
+		Shipping: &validateAddress{},
+	}
+	err := Validate(invalid)
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		t.Fatalf("Validate() = %v, want ValidationErrors", err)
+	}
+	want := map[string][]string{
+		"email":             {"must be a valid email address"},
+		"role":              {"must be one of admin, user"},
+		"website":           {"must be a valid URL"},
+		"address.city":      {"is required"},
+		"address.zip":       {"must have exactly 4 characters"},
+		"items[1].name":     {"must only contain letters"},
+		"items[1].quantity": {"must be at least 1"},
+		"shipping.city":     {"is required"},
+	}
+	if got := errs.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,427 @@
+package there
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+//WebSocket message types, see WebSocketConn.ReadMessage and WebSocketConn.WriteMessage
+const (
+	WebSocketText   = 1
+	WebSocketBinary = 2
+)
+
+//WebSocket close codes defined in RFC 6455
+const (
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketCloseProtocolError   = 1002
+	WebSocketCloseUnsupportedData = 1003
+	WebSocketCloseNoStatus        = 1005
+	WebSocketCloseAbnormal        = 1006
+	WebSocketCloseInvalidPayload  = 1007
+	WebSocketClosePolicyViolation = 1008
+	WebSocketCloseMessageTooBig   = 1009
+	WebSocketCloseInternalError   = 1011
+)
+
+const (
+	webSocketContinuation = 0x0
+	webSocketClose        = 0x8
+	webSocketPing         = 0x9
+	webSocketPong         = 0xA
+
+	webSocketGUID         = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+	webSocketCloseTimeout = 5 * time.Second
+)
+
+//ErrWebSocketHandshake is rendered with StatusBadRequest, if a request to a WebSocket route is no valid upgrade request
+var ErrWebSocketHandshake = errors.New("invalid websocket handshake")
+
+//ErrWebSocketOrigin is rendered with StatusForbidden, if RouterConfiguration.WebSocketCheckOrigin rejects the Origin of an upgrade request
+var ErrWebSocketOrigin = errors.New("websocket origin not allowed")
+
+//SameOrigin is the default RouterConfiguration.WebSocketCheckOrigin. It accepts requests without an Origin header,
+//which are not sent by browsers, and requests whose Origin matches the Host.
+//Otherwise any website could open a WebSocket with the cookies of the user.
+func SameOrigin(request *http.Request) bool {
+	origin := request.Header.Get(RequestHeaderOrigin)
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, request.Host)
+}
+
+//WebSocketCloseError is returned by WebSocketConn.ReadMessage, once the connection was closed with a close frame
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *WebSocketCloseError) Error() string {
+	return "websocket closed with code " + strconv.Itoa(e.Code) + ": " + e.Reason
+}
+
+//WebSocketHandler talks to the client over the upgraded connection. The connection is closed, once the handler returns.
+//The request contains the RouteParams, Params and Headers of the upgrade request.
+type WebSocketHandler func(request HttpRequest, conn *WebSocketConn)
+
+//WebSocket registers a GET route, which upgrades the connection to a WebSocket and passes it to the handler.
+//The handshake happens in the Endpoint, so every Middleware runs beforehand and can reject the request.
+//Upgrades from other origins are rejected by RouterConfiguration.WebSocketCheckOrigin.
+func (group *RouteGroup) WebSocket(path string, handler WebSocketHandler) *RouteRouteGroupBuilder {
+	return group.Get(path, func(request HttpRequest) HttpResponse {
+		return &webSocketResponse{request: request, handler: handler}
+	})
+}
+
+type webSocketResponse struct {
+	request HttpRequest
+	handler WebSocketHandler
+}
+
+func (w webSocketResponse) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(RequestHeaderSecWebSocketVersion) != "13" {
+		WithHeaders(MapString{
+			ResponseHeaderSecWebSocketVersion: "13",
+		}, Error(StatusUpgradeRequired, ErrWebSocketHandshake)).ServeHTTP(rw, r)
+		return
+	}
+	key := r.Header.Get(RequestHeaderSecWebSocketKey)
+	if !headerContainsToken(r.Header, RequestHeaderConnection, "upgrade") ||
+		!headerContainsToken(r.Header, RequestHeaderUpgrade, "websocket") || key == "" {
+		Error(StatusBadRequest, ErrWebSocketHandshake).ServeHTTP(rw, r)
+		return
+	}
+	if checkOrigin := configuration(r).WebSocketCheckOrigin; checkOrigin != nil && !checkOrigin(r) {
+		Error(StatusForbidden, ErrWebSocketOrigin).ServeHTTP(rw, r)
+		return
+	}
+	hijacker, ok := rw.(http.Hijacker)
+	if !ok {
+		Error(StatusInternalServerError, errors.New("the response writer does not support hijacking")).ServeHTTP(rw, r)
+		return
+	}
+	netConn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		Error(StatusInternalServerError, err).ServeHTTP(rw, r)
+		return
+	}
+
+	accept := sha1.Sum([]byte(key + webSocketGUID))
+	buffered.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		ResponseHeaderSecWebSocketAccept + ": " + base64.StdEncoding.EncodeToString(accept[:]) + "\r\n\r\n")
+	if err = buffered.Flush(); err != nil {
+		netConn.Close()
+		return
+	}
+
+	conn := &WebSocketConn{
+		conn:      netConn,
+		reader:    buffered.Reader,
+		readLimit: configuration(r).MaxBodyBytes,
+	}
+	defer conn.Close(WebSocketCloseNormal, "")
+	w.handler(w.request, conn)
+}
+
+//headerContainsToken checks if the comma separated header contains the token, ignoring the case
+func headerContainsToken(header http.Header, key, token string) bool {
+	for _, value := range header.Values(key) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//WebSocketConn is an upgraded WebSocket connection. Reading and writing may happen concurrently,
+//but only one goroutine may read at a time. Pings are answered automatically while reading.
+type WebSocketConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	//readLimit is the maximum size of a message, RouterConfiguration.MaxBodyBytes by default
+	readLimit   int64
+	pongHandler func(data []byte)
+
+	writeMutex sync.Mutex
+	closeSent  bool
+
+	closeMutex sync.Mutex
+	closed     bool
+}
+
+//SetReadLimit sets the maximum size of a message. Larger messages close the connection with WebSocketCloseMessageTooBig.
+//A value of zero or less disables the limit.
+func (c *WebSocketConn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+//SetPongHandler is called with the payload of every pong received while reading
+func (c *WebSocketConn) SetPongHandler(handler func(data []byte)) {
+	c.pongHandler = handler
+}
+
+func (c *WebSocketConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+func (c *WebSocketConn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+func (c *WebSocketConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+//ReadMessage returns the next text or binary message, fragmented messages are joined.
+//Returns a *WebSocketCloseError, if the client closed the connection.
+func (c *WebSocketConn) ReadMessage() (int, []byte, error) {
+	messageType := 0
+	var message []byte
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+		switch opcode {
+		case webSocketPing:
+			if err = c.writeFrame(webSocketPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case webSocketPong:
+			if c.pongHandler != nil {
+				c.pongHandler(payload)
+			}
+			continue
+		case webSocketClose:
+			return 0, nil, c.closeReceived(payload)
+		case webSocketContinuation:
+			if messageType == 0 {
+				return 0, nil, c.fail(WebSocketCloseProtocolError, "unexpected continuation frame")
+			}
+		case WebSocketText, WebSocketBinary:
+			if messageType != 0 {
+				return 0, nil, c.fail(WebSocketCloseProtocolError, "expected continuation frame")
+			}
+			messageType = int(opcode)
+		default:
+			return 0, nil, c.fail(WebSocketCloseProtocolError, "unknown opcode "+strconv.Itoa(int(opcode)))
+		}
+
+		if c.readLimit > 0 && int64(len(message)+len(payload)) > c.readLimit {
+			return 0, nil, c.fail(WebSocketCloseMessageTooBig, "message too big")
+		}
+		message = append(message, payload...)
+		if fin {
+			if messageType == WebSocketText && !utf8.Valid(message) {
+				return 0, nil, c.fail(WebSocketCloseInvalidPayload, "invalid utf-8")
+			}
+			return messageType, message, nil
+		}
+	}
+}
+
+//ReadText returns the next message as string, no matter if it is a text or binary message
+func (c *WebSocketConn) ReadText() (string, error) {
+	_, data, err := c.ReadMessage()
+	return string(data), err
+}
+
+//WriteMessage sends the data as a single text or binary message
+func (c *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != WebSocketText && messageType != WebSocketBinary {
+		return errors.New("message type must be WebSocketText or WebSocketBinary")
+	}
+	return c.writeFrame(byte(messageType), data)
+}
+
+func (c *WebSocketConn) WriteText(text string) error {
+	return c.WriteMessage(WebSocketText, []byte(text))
+}
+
+func (c *WebSocketConn) WriteBinary(data []byte) error {
+	return c.WriteMessage(WebSocketBinary, data)
+}
+
+//Ping sends a ping, the pong of the client is passed to the PongHandler while reading
+func (c *WebSocketConn) Ping(data []byte) error {
+	return c.writeFrame(webSocketPing, data)
+}
+
+//Close sends a close frame with the code and reason, waits briefly for the client to acknowledge it and closes the connection.
+//Calling Close more than once has no effect.
+func (c *WebSocketConn) Close(code int, reason string) error {
+	if !c.markClosed() {
+		return nil
+	}
+	err := c.sendClose(code, reason)
+	if err == nil {
+		// wait for the close frame of the client, the remaining messages are discarded
+		c.conn.SetReadDeadline(time.Now().Add(webSocketCloseTimeout))
+		for {
+			_, opcode, _, readErr := c.readFrame()
+			if readErr != nil || opcode == webSocketClose {
+				break
+			}
+		}
+	}
+	if closeErr := c.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+//markClosed returns true for the first caller, who is responsible for closing the connection
+func (c *WebSocketConn) markClosed() bool {
+	c.closeMutex.Lock()
+	defer c.closeMutex.Unlock()
+	if c.closed {
+		return false
+	}
+	c.closed = true
+	return true
+}
+
+//closeReceived answers the close frame of the client and closes the connection
+func (c *WebSocketConn) closeReceived(payload []byte) error {
+	closeErr := &WebSocketCloseError{Code: WebSocketCloseNoStatus}
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+	}
+	if c.markClosed() {
+		code := closeErr.Code
+		if code == WebSocketCloseNoStatus {
+			code = WebSocketCloseNormal
+		}
+		c.sendClose(code, "")
+		c.conn.Close()
+	}
+	return closeErr
+}
+
+//fail closes the connection because of a misbehaving client and returns the matching WebSocketCloseError
+func (c *WebSocketConn) fail(code int, reason string) error {
+	if c.markClosed() {
+		c.sendClose(code, reason)
+		c.conn.Close()
+	}
+	return &WebSocketCloseError{Code: code, Reason: reason}
+}
+
+func (c *WebSocketConn) sendClose(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+	err := c.writeFrame(webSocketClose, payload)
+	c.writeMutex.Lock()
+	c.closeSent = true
+	c.writeMutex.Unlock()
+	return err
+}
+
+//readFrame reads a single frame and unmasks its payload
+func (c *WebSocketConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(WebSocketCloseProtocolError, "reserved bits are set")
+	}
+	if !masked {
+		return false, 0, nil, c.fail(WebSocketCloseProtocolError, "client frames must be masked")
+	}
+	if opcode >= webSocketClose && (!fin || length > 125) {
+		return false, 0, nil, c.fail(WebSocketCloseProtocolError, "invalid control frame")
+	}
+
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(extended[:]))
+	}
+	if length < 0 || (c.readLimit > 0 && length > c.readLimit) {
+		return false, 0, nil, c.fail(WebSocketCloseMessageTooBig, "message too big")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	//the buffer grows with the received data instead of the length the client declared
+	var buffer bytes.Buffer
+	if _, err := io.CopyN(&buffer, c.reader, length); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return false, 0, nil, err
+	}
+	payload := buffer.Bytes()
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+//writeFrame sends the payload as a single unmasked frame
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+
+	frame := make([]byte, 0, 10+len(payload))
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, 126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	default:
+		frame = append(frame, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(frame[2:], uint64(len(payload)))
+	}
+	frame = append(frame, payload...)
+	_, err := c.conn.Write(frame)
+	return err
+}
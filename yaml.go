@@ -0,0 +1,118 @@
+package there
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+//MarshalYaml is the Serializer registered for ContentTypeApplicationYaml. The data is marshaled like by encoding/json,
+//so json tags and json.Marshaler are respected, and written as block style YAML:
+//
+//	name: Hannes
+//	roles:
+//	  - admin
+func MarshalYaml(data any) ([]byte, error) {
+	tree, err := jsonTree(data)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if isYamlBlock(tree) {
+		writeYamlBlock(&buffer, tree, 0, false)
+	} else {
+		buffer.WriteString(yamlScalar(tree) + "\n")
+	}
+	return buffer.Bytes(), nil
+}
+
+//isYamlBlock reports, if the value is written as block collection instead of a single line
+func isYamlBlock(value any) bool {
+	switch v := value.(type) {
+	case orderedObject:
+		return len(v) > 0
+	case []any:
+		return len(v) > 0
+	}
+	return false
+}
+
+//writeYamlBlock writes a non-empty object or array. If inline is true, the first line continues the current one, e.g. after "- ".
+func writeYamlBlock(buffer *bytes.Buffer, value any, indent int, inline bool) {
+	padding := strings.Repeat(" ", indent)
+	line := func() {
+		if inline {
+			inline = false
+			return
+		}
+		buffer.WriteString(padding)
+	}
+	switch v := value.(type) {
+	case orderedObject:
+		for _, field := range v {
+			line()
+			buffer.WriteString(yamlString(field.key) + ":")
+			if isYamlBlock(field.value) {
+				buffer.WriteString("\n")
+				writeYamlBlock(buffer, field.value, indent+2, false)
+			} else {
+				buffer.WriteString(" " + yamlScalar(field.value) + "\n")
+			}
+		}
+	case []any:
+		for _, item := range v {
+			line()
+			buffer.WriteString("- ")
+			if isYamlBlock(item) {
+				writeYamlBlock(buffer, item, indent+2, true)
+			} else {
+				buffer.WriteString(yamlScalar(item) + "\n")
+			}
+		}
+	}
+}
+
+func yamlScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	case orderedObject:
+		return "{}"
+	case []any:
+		return "[]"
+	}
+	return ""
+}
+
+//yamlReserved are plain scalars, which YAML would not read as string
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true, "y": true, "n": true, "null": true,
+}
+
+//yamlString writes simple words plain and quotes everything else, e.g. numbers, booleans or special characters.
+//Double quoted YAML accepts the escapes of JSON.
+func yamlString(value string) string {
+	plain := value != "" && !yamlReserved[strings.ToLower(value)] && strings.TrimSpace(value) == value
+	for i, r := range value {
+		if !plain {
+			break
+		}
+		first := i == 0 && (unicode.IsLetter(r) || r == '_')
+		plain = first || (i > 0 && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" _-./", r)))
+	}
+	if plain {
+		return value
+	}
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}